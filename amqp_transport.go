@@ -0,0 +1,170 @@
+package dbm
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"github.com/streadway/amqp"
+)
+
+// ErrTransportClosed возвращается для запросов, ответ на которые не может быть получен
+// из-за закрытия транспорта или потери соединения с брокером сообщений.
+// Транспорт не восстанавливает соединение: после закрытия все запросы завершаются этой ошибкой.
+var ErrTransportClosed = errors.New("transport is closed")
+
+// ErrPublishFailed возвращается, если брокер сообщений не принял запрос к публикации.
+// Запрос при этом не доставлен сервису.
+var ErrPublishFailed = errors.New("failed to publish request")
+
+// AMQPTransport передает запросы сервису через брокер сообщений RabbitMQ.
+// В отличие от srv.RPCClient ошибки соединения возвращаются вызывающему коду,
+// а ожидание ответа ограничивается контекстом запроса.
+type AMQPTransport struct {
+	conn    *amqp.Connection
+	ch      *amqp.Channel
+	replyTo string
+	pubMu   sync.Mutex
+
+	mu      sync.Mutex
+	pending map[string]chan []byte
+	closed  bool
+	done    chan struct{}
+}
+
+var _ Transport = (*AMQPTransport)(nil)
+
+// NewAMQPTransport подключается к брокеру сообщений `connstr` и создает очередь для
+// получения ответов сервиса.
+func NewAMQPTransport(connstr string) (_ *AMQPTransport, err error) {
+	t := &AMQPTransport{pending: map[string]chan []byte{}, done: make(chan struct{})}
+	if t.conn, err = amqp.Dial(connstr); err != nil {
+		return nil, errors.Wrap(err, "failed to connect to RabbitMQ")
+	}
+	defer func() {
+		if err != nil {
+			t.conn.Close()
+		}
+	}()
+
+	if t.ch, err = t.conn.Channel(); err != nil {
+		return nil, errors.Wrap(err, "failed to open a channel")
+	}
+	q, err := t.ch.QueueDeclare(
+		"",    // name
+		false, // durable
+		false, // delete when unused
+		true,  // exclusive
+		false, // noWait
+		nil,   // arguments
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to declare a queue")
+	}
+	msgs, err := t.ch.Consume(
+		q.Name, // queue
+		"",     // consumer
+		true,   // auto-ack
+		false,  // exclusive
+		false,  // no-local
+		false,  // no-wait
+		nil,    // args
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to register a consumer")
+	}
+	t.replyTo = q.Name
+
+	go t.receive(msgs)
+	return t, nil
+}
+
+// Передает полученные ответы ожидающим их запросам.
+// При закрытии канала доставки ожидающие запросы завершаются с ошибкой ErrTransportClosed.
+func (t *AMQPTransport) receive(msgs <-chan amqp.Delivery) {
+	defer close(t.done)
+	for d := range msgs {
+		t.mu.Lock()
+		answer, ok := t.pending[d.CorrelationId]
+		delete(t.pending, d.CorrelationId)
+		t.mu.Unlock()
+		if ok {
+			answer <- d.Body
+		}
+	}
+
+	t.mu.Lock()
+	t.closed = true
+	for id, answer := range t.pending {
+		close(answer)
+		delete(t.pending, id)
+	}
+	t.mu.Unlock()
+}
+
+// RoundTrip публикует запрос в очередь сервиса и ожидает ответ.
+// Срок действия контекста передается брокеру как срок жизни сообщения запроса.
+func (t *AMQPTransport) RoundTrip(ctx context.Context, body []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	correlationID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	id := correlationID.String()
+
+	answer := make(chan []byte, 1)
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil, ErrTransportClosed
+	}
+	t.pending[id] = answer
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		delete(t.pending, id)
+		t.mu.Unlock()
+	}()
+
+	msg := amqp.Publishing{
+		ContentType:   "application/json",
+		CorrelationId: id,
+		ReplyTo:       t.replyTo,
+		Body:          body,
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		ttl := time.Until(deadline).Milliseconds()
+		if ttl < 1 {
+			ttl = 1
+		}
+		msg.Expiration = strconv.FormatInt(ttl, 10)
+	}
+	t.pubMu.Lock()
+	err = t.ch.Publish("", ServiceName, false, false, msg)
+	t.pubMu.Unlock()
+	if err != nil {
+		return nil, errors.Wrap(ErrPublishFailed, err.Error())
+	}
+
+	select {
+	case data, ok := <-answer:
+		if !ok {
+			return nil, ErrTransportClosed
+		}
+		return data, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Close закрывает соединение с брокером сообщений.
+func (t *AMQPTransport) Close() error {
+	err := t.conn.Close()
+	<-t.done
+	return err
+}
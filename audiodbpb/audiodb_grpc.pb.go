@@ -0,0 +1,741 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: audiodb.proto
+
+package audiodbpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// AudioDBClient is the client API for AudioDB service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AudioDBClient interface {
+	Ping(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetEntry(ctx context.Context, in *EntryRef, opts ...grpc.CallOption) (*EntryData, error)
+	SetEntry(ctx context.Context, in *EntryData, opts ...grpc.CallOption) (*EntryData, error)
+	DeleteEntry(ctx context.Context, in *EntryRef, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	FinalyzeEntry(ctx context.Context, in *EntryRef, opts ...grpc.CallOption) (*EntryData, error)
+	UnfinalyzeEntry(ctx context.Context, in *EntryRef, opts ...grpc.CallOption) (*EntryData, error)
+	RenameEntry(ctx context.Context, in *RenameEntryRequest, opts ...grpc.CallOption) (*AlbumEntry, error)
+	MoveTree(ctx context.Context, in *MoveTreeRequest, opts ...grpc.CallOption) (*MoveTreeResponse, error)
+	Reconcile(ctx context.Context, in *ReconcileRequest, opts ...grpc.CallOption) (*ReconcileReport, error)
+	// Передает все каталоги, соответствующие фильтру, начиная с позиции `cursor`.
+	ListEntries(ctx context.Context, in *EntryFilter, opts ...grpc.CallOption) (AudioDB_ListEntriesClient, error)
+	// Передает каталоги, соответствующие фильтру, со всеми связанными данными.
+	ExportEntries(ctx context.Context, in *EntryFilter, opts ...grpc.CallOption) (AudioDB_ExportEntriesClient, error)
+	SearchEntries(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	AcceptSuggestion(ctx context.Context, in *SuggestionRequest, opts ...grpc.CallOption) (*EntryData, error)
+	RejectSuggestion(ctx context.Context, in *SuggestionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetEntryHistory(ctx context.Context, in *EntryRef, opts ...grpc.CallOption) (*EntryHistory, error)
+	DiffEntryRevisions(ctx context.Context, in *DiffRequest, opts ...grpc.CallOption) (*DiffResponse, error)
+	RevertEntry(ctx context.Context, in *RevertRequest, opts ...grpc.CallOption) (*EntryData, error)
+}
+
+type audioDBClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAudioDBClient(cc grpc.ClientConnInterface) AudioDBClient {
+	return &audioDBClient{cc}
+}
+
+func (c *audioDBClient) Ping(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/audiodb.v1.AudioDB/Ping", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioDBClient) GetEntry(ctx context.Context, in *EntryRef, opts ...grpc.CallOption) (*EntryData, error) {
+	out := new(EntryData)
+	err := c.cc.Invoke(ctx, "/audiodb.v1.AudioDB/GetEntry", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioDBClient) SetEntry(ctx context.Context, in *EntryData, opts ...grpc.CallOption) (*EntryData, error) {
+	out := new(EntryData)
+	err := c.cc.Invoke(ctx, "/audiodb.v1.AudioDB/SetEntry", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioDBClient) DeleteEntry(ctx context.Context, in *EntryRef, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/audiodb.v1.AudioDB/DeleteEntry", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioDBClient) FinalyzeEntry(ctx context.Context, in *EntryRef, opts ...grpc.CallOption) (*EntryData, error) {
+	out := new(EntryData)
+	err := c.cc.Invoke(ctx, "/audiodb.v1.AudioDB/FinalyzeEntry", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioDBClient) UnfinalyzeEntry(ctx context.Context, in *EntryRef, opts ...grpc.CallOption) (*EntryData, error) {
+	out := new(EntryData)
+	err := c.cc.Invoke(ctx, "/audiodb.v1.AudioDB/UnfinalyzeEntry", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioDBClient) RenameEntry(ctx context.Context, in *RenameEntryRequest, opts ...grpc.CallOption) (*AlbumEntry, error) {
+	out := new(AlbumEntry)
+	err := c.cc.Invoke(ctx, "/audiodb.v1.AudioDB/RenameEntry", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioDBClient) MoveTree(ctx context.Context, in *MoveTreeRequest, opts ...grpc.CallOption) (*MoveTreeResponse, error) {
+	out := new(MoveTreeResponse)
+	err := c.cc.Invoke(ctx, "/audiodb.v1.AudioDB/MoveTree", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioDBClient) Reconcile(ctx context.Context, in *ReconcileRequest, opts ...grpc.CallOption) (*ReconcileReport, error) {
+	out := new(ReconcileReport)
+	err := c.cc.Invoke(ctx, "/audiodb.v1.AudioDB/Reconcile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioDBClient) ListEntries(ctx context.Context, in *EntryFilter, opts ...grpc.CallOption) (AudioDB_ListEntriesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AudioDB_ServiceDesc.Streams[0], "/audiodb.v1.AudioDB/ListEntries", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &audioDBListEntriesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AudioDB_ListEntriesClient interface {
+	Recv() (*AlbumEntry, error)
+	grpc.ClientStream
+}
+
+type audioDBListEntriesClient struct {
+	grpc.ClientStream
+}
+
+func (x *audioDBListEntriesClient) Recv() (*AlbumEntry, error) {
+	m := new(AlbumEntry)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *audioDBClient) ExportEntries(ctx context.Context, in *EntryFilter, opts ...grpc.CallOption) (AudioDB_ExportEntriesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AudioDB_ServiceDesc.Streams[1], "/audiodb.v1.AudioDB/ExportEntries", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &audioDBExportEntriesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AudioDB_ExportEntriesClient interface {
+	Recv() (*EntryData, error)
+	grpc.ClientStream
+}
+
+type audioDBExportEntriesClient struct {
+	grpc.ClientStream
+}
+
+func (x *audioDBExportEntriesClient) Recv() (*EntryData, error) {
+	m := new(EntryData)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *audioDBClient) SearchEntries(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, "/audiodb.v1.AudioDB/SearchEntries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioDBClient) AcceptSuggestion(ctx context.Context, in *SuggestionRequest, opts ...grpc.CallOption) (*EntryData, error) {
+	out := new(EntryData)
+	err := c.cc.Invoke(ctx, "/audiodb.v1.AudioDB/AcceptSuggestion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioDBClient) RejectSuggestion(ctx context.Context, in *SuggestionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/audiodb.v1.AudioDB/RejectSuggestion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioDBClient) GetEntryHistory(ctx context.Context, in *EntryRef, opts ...grpc.CallOption) (*EntryHistory, error) {
+	out := new(EntryHistory)
+	err := c.cc.Invoke(ctx, "/audiodb.v1.AudioDB/GetEntryHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioDBClient) DiffEntryRevisions(ctx context.Context, in *DiffRequest, opts ...grpc.CallOption) (*DiffResponse, error) {
+	out := new(DiffResponse)
+	err := c.cc.Invoke(ctx, "/audiodb.v1.AudioDB/DiffEntryRevisions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioDBClient) RevertEntry(ctx context.Context, in *RevertRequest, opts ...grpc.CallOption) (*EntryData, error) {
+	out := new(EntryData)
+	err := c.cc.Invoke(ctx, "/audiodb.v1.AudioDB/RevertEntry", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AudioDBServer is the server API for AudioDB service.
+// All implementations must embed UnimplementedAudioDBServer
+// for forward compatibility
+type AudioDBServer interface {
+	Ping(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	GetEntry(context.Context, *EntryRef) (*EntryData, error)
+	SetEntry(context.Context, *EntryData) (*EntryData, error)
+	DeleteEntry(context.Context, *EntryRef) (*emptypb.Empty, error)
+	FinalyzeEntry(context.Context, *EntryRef) (*EntryData, error)
+	UnfinalyzeEntry(context.Context, *EntryRef) (*EntryData, error)
+	RenameEntry(context.Context, *RenameEntryRequest) (*AlbumEntry, error)
+	MoveTree(context.Context, *MoveTreeRequest) (*MoveTreeResponse, error)
+	Reconcile(context.Context, *ReconcileRequest) (*ReconcileReport, error)
+	// Передает все каталоги, соответствующие фильтру, начиная с позиции `cursor`.
+	ListEntries(*EntryFilter, AudioDB_ListEntriesServer) error
+	// Передает каталоги, соответствующие фильтру, со всеми связанными данными.
+	ExportEntries(*EntryFilter, AudioDB_ExportEntriesServer) error
+	SearchEntries(context.Context, *SearchRequest) (*SearchResponse, error)
+	AcceptSuggestion(context.Context, *SuggestionRequest) (*EntryData, error)
+	RejectSuggestion(context.Context, *SuggestionRequest) (*emptypb.Empty, error)
+	GetEntryHistory(context.Context, *EntryRef) (*EntryHistory, error)
+	DiffEntryRevisions(context.Context, *DiffRequest) (*DiffResponse, error)
+	RevertEntry(context.Context, *RevertRequest) (*EntryData, error)
+	mustEmbedUnimplementedAudioDBServer()
+}
+
+// UnimplementedAudioDBServer must be embedded to have forward compatible implementations.
+type UnimplementedAudioDBServer struct {
+}
+
+func (UnimplementedAudioDBServer) Ping(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
+}
+func (UnimplementedAudioDBServer) GetEntry(context.Context, *EntryRef) (*EntryData, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEntry not implemented")
+}
+func (UnimplementedAudioDBServer) SetEntry(context.Context, *EntryData) (*EntryData, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetEntry not implemented")
+}
+func (UnimplementedAudioDBServer) DeleteEntry(context.Context, *EntryRef) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteEntry not implemented")
+}
+func (UnimplementedAudioDBServer) FinalyzeEntry(context.Context, *EntryRef) (*EntryData, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FinalyzeEntry not implemented")
+}
+func (UnimplementedAudioDBServer) UnfinalyzeEntry(context.Context, *EntryRef) (*EntryData, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnfinalyzeEntry not implemented")
+}
+func (UnimplementedAudioDBServer) RenameEntry(context.Context, *RenameEntryRequest) (*AlbumEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameEntry not implemented")
+}
+func (UnimplementedAudioDBServer) MoveTree(context.Context, *MoveTreeRequest) (*MoveTreeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveTree not implemented")
+}
+func (UnimplementedAudioDBServer) Reconcile(context.Context, *ReconcileRequest) (*ReconcileReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reconcile not implemented")
+}
+func (UnimplementedAudioDBServer) ListEntries(*EntryFilter, AudioDB_ListEntriesServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListEntries not implemented")
+}
+func (UnimplementedAudioDBServer) ExportEntries(*EntryFilter, AudioDB_ExportEntriesServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportEntries not implemented")
+}
+func (UnimplementedAudioDBServer) SearchEntries(context.Context, *SearchRequest) (*SearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchEntries not implemented")
+}
+func (UnimplementedAudioDBServer) AcceptSuggestion(context.Context, *SuggestionRequest) (*EntryData, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcceptSuggestion not implemented")
+}
+func (UnimplementedAudioDBServer) RejectSuggestion(context.Context, *SuggestionRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectSuggestion not implemented")
+}
+func (UnimplementedAudioDBServer) GetEntryHistory(context.Context, *EntryRef) (*EntryHistory, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEntryHistory not implemented")
+}
+func (UnimplementedAudioDBServer) DiffEntryRevisions(context.Context, *DiffRequest) (*DiffResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffEntryRevisions not implemented")
+}
+func (UnimplementedAudioDBServer) RevertEntry(context.Context, *RevertRequest) (*EntryData, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevertEntry not implemented")
+}
+func (UnimplementedAudioDBServer) mustEmbedUnimplementedAudioDBServer() {}
+
+// UnsafeAudioDBServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AudioDBServer will
+// result in compilation errors.
+type UnsafeAudioDBServer interface {
+	mustEmbedUnimplementedAudioDBServer()
+}
+
+func RegisterAudioDBServer(s grpc.ServiceRegistrar, srv AudioDBServer) {
+	s.RegisterService(&AudioDB_ServiceDesc, srv)
+}
+
+func _AudioDB_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioDBServer).Ping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/audiodb.v1.AudioDB/Ping",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioDBServer).Ping(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioDB_GetEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EntryRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioDBServer).GetEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/audiodb.v1.AudioDB/GetEntry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioDBServer).GetEntry(ctx, req.(*EntryRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioDB_SetEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EntryData)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioDBServer).SetEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/audiodb.v1.AudioDB/SetEntry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioDBServer).SetEntry(ctx, req.(*EntryData))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioDB_DeleteEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EntryRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioDBServer).DeleteEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/audiodb.v1.AudioDB/DeleteEntry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioDBServer).DeleteEntry(ctx, req.(*EntryRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioDB_FinalyzeEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EntryRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioDBServer).FinalyzeEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/audiodb.v1.AudioDB/FinalyzeEntry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioDBServer).FinalyzeEntry(ctx, req.(*EntryRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioDB_UnfinalyzeEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EntryRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioDBServer).UnfinalyzeEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/audiodb.v1.AudioDB/UnfinalyzeEntry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioDBServer).UnfinalyzeEntry(ctx, req.(*EntryRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioDB_RenameEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioDBServer).RenameEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/audiodb.v1.AudioDB/RenameEntry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioDBServer).RenameEntry(ctx, req.(*RenameEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioDB_MoveTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveTreeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioDBServer).MoveTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/audiodb.v1.AudioDB/MoveTree",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioDBServer).MoveTree(ctx, req.(*MoveTreeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioDB_Reconcile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReconcileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioDBServer).Reconcile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/audiodb.v1.AudioDB/Reconcile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioDBServer).Reconcile(ctx, req.(*ReconcileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioDB_ListEntries_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EntryFilter)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AudioDBServer).ListEntries(m, &audioDBListEntriesServer{stream})
+}
+
+type AudioDB_ListEntriesServer interface {
+	Send(*AlbumEntry) error
+	grpc.ServerStream
+}
+
+type audioDBListEntriesServer struct {
+	grpc.ServerStream
+}
+
+func (x *audioDBListEntriesServer) Send(m *AlbumEntry) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AudioDB_ExportEntries_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EntryFilter)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AudioDBServer).ExportEntries(m, &audioDBExportEntriesServer{stream})
+}
+
+type AudioDB_ExportEntriesServer interface {
+	Send(*EntryData) error
+	grpc.ServerStream
+}
+
+type audioDBExportEntriesServer struct {
+	grpc.ServerStream
+}
+
+func (x *audioDBExportEntriesServer) Send(m *EntryData) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AudioDB_SearchEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioDBServer).SearchEntries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/audiodb.v1.AudioDB/SearchEntries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioDBServer).SearchEntries(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioDB_AcceptSuggestion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioDBServer).AcceptSuggestion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/audiodb.v1.AudioDB/AcceptSuggestion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioDBServer).AcceptSuggestion(ctx, req.(*SuggestionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioDB_RejectSuggestion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioDBServer).RejectSuggestion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/audiodb.v1.AudioDB/RejectSuggestion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioDBServer).RejectSuggestion(ctx, req.(*SuggestionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioDB_GetEntryHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EntryRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioDBServer).GetEntryHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/audiodb.v1.AudioDB/GetEntryHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioDBServer).GetEntryHistory(ctx, req.(*EntryRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioDB_DiffEntryRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioDBServer).DiffEntryRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/audiodb.v1.AudioDB/DiffEntryRevisions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioDBServer).DiffEntryRevisions(ctx, req.(*DiffRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioDB_RevertEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioDBServer).RevertEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/audiodb.v1.AudioDB/RevertEntry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioDBServer).RevertEntry(ctx, req.(*RevertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AudioDB_ServiceDesc is the grpc.ServiceDesc for AudioDB service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AudioDB_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "audiodb.v1.AudioDB",
+	HandlerType: (*AudioDBServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Ping",
+			Handler:    _AudioDB_Ping_Handler,
+		},
+		{
+			MethodName: "GetEntry",
+			Handler:    _AudioDB_GetEntry_Handler,
+		},
+		{
+			MethodName: "SetEntry",
+			Handler:    _AudioDB_SetEntry_Handler,
+		},
+		{
+			MethodName: "DeleteEntry",
+			Handler:    _AudioDB_DeleteEntry_Handler,
+		},
+		{
+			MethodName: "FinalyzeEntry",
+			Handler:    _AudioDB_FinalyzeEntry_Handler,
+		},
+		{
+			MethodName: "UnfinalyzeEntry",
+			Handler:    _AudioDB_UnfinalyzeEntry_Handler,
+		},
+		{
+			MethodName: "RenameEntry",
+			Handler:    _AudioDB_RenameEntry_Handler,
+		},
+		{
+			MethodName: "MoveTree",
+			Handler:    _AudioDB_MoveTree_Handler,
+		},
+		{
+			MethodName: "Reconcile",
+			Handler:    _AudioDB_Reconcile_Handler,
+		},
+		{
+			MethodName: "SearchEntries",
+			Handler:    _AudioDB_SearchEntries_Handler,
+		},
+		{
+			MethodName: "AcceptSuggestion",
+			Handler:    _AudioDB_AcceptSuggestion_Handler,
+		},
+		{
+			MethodName: "RejectSuggestion",
+			Handler:    _AudioDB_RejectSuggestion_Handler,
+		},
+		{
+			MethodName: "GetEntryHistory",
+			Handler:    _AudioDB_GetEntryHistory_Handler,
+		},
+		{
+			MethodName: "DiffEntryRevisions",
+			Handler:    _AudioDB_DiffEntryRevisions_Handler,
+		},
+		{
+			MethodName: "RevertEntry",
+			Handler:    _AudioDB_RevertEntry_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListEntries",
+			Handler:       _AudioDB_ListEntries_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportEntries",
+			Handler:       _AudioDB_ExportEntries_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "audiodb.proto",
+}
@@ -0,0 +1,5 @@
+// Пакет audiodbpb содержит определения protobuf и код gRPC, сгенерированный по контракту
+// audiodb.proto сервиса dbmaudio.
+package audiodbpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative audiodb.proto
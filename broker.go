@@ -0,0 +1,87 @@
+package dbm
+
+import (
+	"github.com/streadway/amqp"
+
+	srv "github.com/ytsiuryn/ds-microservice"
+)
+
+// Тег потребителя очереди запросов сервиса.
+const consumerTag = ServiceName + "-consumer"
+
+// connectToMessageBroker подключает сервис к брокеру сообщений.
+// В отличие от `srv.Service.ConnectToMessageBroker` число неподтвержденных сообщений,
+// получаемых от брокера, ограничивается значением `prefetch`, что позволяет обрабатывать
+// несколько запросов одновременно.
+func (m *Dbm) connectToMessageBroker(connstr string, prefetch int) <-chan amqp.Delivery {
+	var err error
+
+	m.amqpConn, err = amqp.Dial(connstr)
+	srv.FailOnError(err, "Failed to connect to RabbitMQ")
+
+	m.amqpCh, err = m.amqpConn.Channel()
+	srv.FailOnError(err, "Failed to open a channel")
+
+	q, err := m.amqpCh.QueueDeclare(
+		m.Name, // name
+		false,  // durable
+		false,  // delete when unused
+		false,  // exclusive
+		false,  // no-wait
+		nil,    // arguments
+	)
+	srv.FailOnError(err, "Failed to declare a queue")
+
+	err = m.amqpCh.Qos(
+		prefetch, // prefetch count
+		0,        // prefetch size
+		false,    // global
+	)
+	srv.FailOnError(err, "Failed to set QoS")
+
+	msgs, err := m.amqpCh.Consume(
+		q.Name,      // queue
+		consumerTag, // consumer
+		false,       // auto ack
+		false,       // exclusive
+		false,       // no local
+		false,       // no wait
+		nil,         // args
+	)
+	srv.FailOnError(err, "Failed to register a consumer")
+
+	return msgs
+}
+
+// Answer отправляет клиенту ответ `result` в JSON формате в соответствии с идентификатором
+// запроса CorrelationId в параметре delivery и подтверждает обработку запроса.
+// В случае ошибки отправки работа сервиса прекращается.
+func (m *Dbm) Answer(delivery *amqp.Delivery, result []byte) {
+	err := m.amqpCh.Publish(
+		"",
+		delivery.ReplyTo,
+		false,
+		false,
+		amqp.Publishing{
+			ContentType:   "application/json",
+			CorrelationId: delivery.CorrelationId,
+			Body:          result,
+		})
+	srv.FailOnError(err, "Answer's publishing error")
+
+	srv.FailOnError(delivery.Ack(false), "Acknowledge error")
+}
+
+// Прекращает прием новых запросов от брокера сообщений.
+func (m *Dbm) stopConsuming() {
+	m.LogOnErrorWithContext(m.amqpCh.Cancel(consumerTag, false), "Consumer cancelling")
+}
+
+// Освобождает ресурсы подключения к брокеру сообщений.
+func (m *Dbm) disconnectFromMessageBroker() {
+	if m.amqpConn == nil {
+		return
+	}
+	m.amqpCh.Close()
+	m.amqpConn.Close()
+}
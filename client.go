@@ -17,19 +17,47 @@ import (
 // Объект этого типа может использоваться клиентом сервиса как "долгоиграющий"
 // с динамическим обновлением исходных метаданных.
 type AudioDBRequest struct {
-	Cmd            string                  `json:"cmd"`
-	NewPath        string                  `json:"new_path,omitempty"`
-	Entry          *entity.AlbumEntry      `json:"entry,omitempty"`
-	Suggestions    []*entity.Suggestion    `json:"suggestions,omitempty"`
-	BadSuggestions []*entity.BadSuggestion `json:"bad_suggestions,omitempty"`
-	Actors         []*entity.Actor         `json:"actors,omitempty"`
-	Pictures       []*entity.Picture       `json:"pictures,omitempty"`
+	Cmd            string                     `json:"cmd"`
+	NewPath        string                     `json:"new_path,omitempty"`
+	Entry          *entity.AlbumEntry         `json:"entry,omitempty"`
+	Suggestions    []*entity.Suggestion       `json:"suggestions,omitempty"`
+	BadSuggestions []*entity.BadSuggestion    `json:"bad_suggestions,omitempty"`
+	Actors         []*entity.Actor            `json:"actors,omitempty"`
+	Pictures       []*entity.Picture          `json:"pictures,omitempty"`
+	Filter         *entity.EntryFilter        `json:"filter,omitempty"`
+	Entries        []*entity.AlbumEntry       `json:"entries,omitempty"`
+	NextCursor     string                     `json:"next_cursor,omitempty"`
+	Search         *entity.EntrySearch        `json:"search,omitempty"`
+	SearchResults  []*entity.SearchResult     `json:"search_results,omitempty"`
+	ExtDB          string                     `json:"ext_db,omitempty"`
+	ExtID          string                     `json:"ext_id,omitempty"`
+	MergePolicy    string                     `json:"merge_policy,omitempty"`
+	Accepted       *entity.AcceptedSuggestion `json:"accepted,omitempty"`
+	RevisionID     int                        `json:"revision_id,omitempty"`
+	ToRevisionID   int                        `json:"to_revision_id,omitempty"`
+	Revisions      []*entity.EntryRevision    `json:"revisions,omitempty"`
+	Diff           []*JSONChange              `json:"diff,omitempty"`
+	Transitions    []string                   `json:"transitions,omitempty"`
+	MissingTags    []string                   `json:"missing_tags,omitempty"`
+	OldPrefix      string                     `json:"old_prefix,omitempty"`
+	NewPrefix      string                     `json:"new_prefix,omitempty"`
+	DryRun         bool                       `json:"dry_run,omitempty"`
+	Moves          []*entity.EntryMove        `json:"moves,omitempty"`
+	Collisions     []string                   `json:"collisions,omitempty"`
+	OrphanAction   string                     `json:"orphan_action,omitempty"`
+	Reconcile      *ReconcileReport           `json:"reconcile,omitempty"`
 }
 
 // AudioDBResponse описывает структуру ответа
 type AudioDBResponse struct {
 	*AudioDBRequest
-	Error *srv.ErrorResponse `json:"error,omitempty"`
+	Error *ErrorResponse `json:"error,omitempty"`
+}
+
+// ErrorResponse описывает ошибку выполнения команды с машиночитаемым кодом ошибки.
+type ErrorResponse struct {
+	srv.ErrorResponse
+	Code ErrorCode `json:"code,omitempty"`
 }
 
 // NewAudioDBRequest создает объект запроса.
@@ -81,6 +109,43 @@ func (req *AudioDBRequest) ImportSuggestions(set *md.SuggestionSet) error {
 	return nil
 }
 
+// ExportAssumption формирует из данных ответа метаданные `md.Assumption`: релиз из Entry.Json,
+// изображения каталога и акторов с признаком entity.AlbumEntryEntity.
+// Выполняет обратное ImportAssumption() преобразование.
+func (resp *AudioDBResponse) ExportAssumption() (*md.Assumption, error) {
+	assumption := md.NewAssumption(nil)
+	if resp.Entry != nil && len(resp.Entry.Json) > 0 {
+		if err := json.Unmarshal(resp.Entry.Json, assumption.Release); err != nil {
+			return nil, err
+		}
+	}
+	for _, pict := range resp.Pictures {
+		if pict.EntType == "album_entry" {
+			assumption.Pictures = append(assumption.Pictures, pict.PictureInAudio())
+		}
+	}
+	assumption.Actors = resp.actorIDs(entity.AlbumEntryEntity)
+	return assumption, nil
+}
+
+// ExportSuggestionSet формирует из данных ответа набор предложений `md.SuggestionSet`
+// с акторами, имеющими признак entity.SuggestionEntity.
+// Выполняет обратное ImportSuggestions() преобразование.
+func (resp *AudioDBResponse) ExportSuggestionSet() (*md.SuggestionSet, error) {
+	set := md.NewSuggestionSet()
+	for _, s := range resp.Suggestions {
+		suggestion := md.NewSuggestion()
+		if err := json.Unmarshal(s.Json, suggestion.Release); err != nil {
+			return nil, err
+		}
+		suggestion.ServiceName = s.ExtDB
+		suggestion.SourceSimilarity = s.Score
+		set.Suggestions = append(set.Suggestions, suggestion)
+	}
+	set.Actors = resp.actorIDs(entity.SuggestionEntity)
+	return set, nil
+}
+
 // Create генерирует CorrelationID и дамп данных для запроса.
 func (req *AudioDBRequest) Create() (_ string, data []byte, err error) {
 	correlationID, err := uuid.NewV4()
@@ -133,6 +198,7 @@ func (req *AudioDBRequest) mergeActors(actors md.ActorIDs, mask entity.EntityMas
 				EntityMask: mask}
 			req.Actors = append(req.Actors, actor)
 		}
+		actor.EntityMask |= mask
 		ids := actor.IDs
 		for extDB, id := range actorIDs {
 			var found bool
@@ -152,16 +218,33 @@ func (req *AudioDBRequest) mergeActors(actors md.ActorIDs, mask entity.EntityMas
 
 func (req *AudioDBRequest) clearActorWithMask(mask entity.EntityMask) {
 	for i := len(req.Actors) - 1; i >= 0; i-- {
-		if req.Actors[i].EntityMask == mask {
+		req.Actors[i].EntityMask &^= mask
+		if req.Actors[i].EntityMask == 0 {
 			req.Actors = append(req.Actors[:i], req.Actors[i+1:]...)
-		} else {
-			req.Actors[i].EntityMask ^= mask
 		}
 	}
 }
 
+// Возвращает коды во внешних БД для акторов с признаком `mask`.
+func (req *AudioDBRequest) actorIDs(mask entity.EntityMask) md.ActorIDs {
+	ret := md.ActorIDs{}
+	for _, actor := range req.Actors {
+		if actor.EntityMask&mask == 0 {
+			continue
+		}
+		ids := md.IDs{}
+		for _, pair := range actor.IDs {
+			ids[pair[0]] = pair[1]
+		}
+		ret[actor.Name] = ids
+	}
+	return ret
+}
+
 // MustBeOK контроллирует значение ответа микросервиса, и, в случае ошибки,
 // печатает сведения об ошибке и останавливает процесс с запущенным клиентом.
+//
+// Deprecated: используйте Err() или Client, возвращающие ошибку вызывающему коду.
 func (resp *AudioDBResponse) Unwrap() *AudioDBRequest {
 	if resp.Error != nil {
 		srv.FailOnError(errors.New(resp.Error.Error), resp.Error.Context)
@@ -169,6 +252,18 @@ func (resp *AudioDBResponse) Unwrap() *AudioDBRequest {
 	return resp.AudioDBRequest
 }
 
+// Err возвращает ошибку выполнения команды из ответа сервиса или nil.
+// Тип ошибки - *ResponseError.
+func (resp *AudioDBResponse) Err() error {
+	if resp.Error == nil {
+		return nil
+	}
+	return &ResponseError{
+		Code:    resp.Error.Code,
+		Message: resp.Error.Error,
+		Context: resp.Error.Context}
+}
+
 // ParseAnswer разбирает JSON ответа и импортирует данные в объект `AudioDBRequest`.
 func ParseAnswer(data []byte) (_ *AudioDBResponse, err error) {
 	entry := AudioDBResponse{}
@@ -0,0 +1,132 @@
+package dbm
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+	md "github.com/ytsiuryn/ds-audiomd"
+)
+
+func readTestAssumption(t *testing.T) *md.Assumption {
+	data, err := ioutil.ReadFile("testdata/test_assumption.json")
+	require.NoError(t, err)
+	assumption := md.NewAssumption(nil)
+	require.NoError(t, json.Unmarshal(data, assumption))
+	return assumption
+}
+
+// Передает запрос в формате ответа сервиса.
+func asResponse(t *testing.T, req *AudioDBRequest) *AudioDBResponse {
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+	resp, err := ParseAnswer(data)
+	require.NoError(t, err)
+	return resp
+}
+
+func releaseJSON(t *testing.T, r *md.Release) string {
+	data, err := json.Marshal(r)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestExportAssumption(t *testing.T) {
+	assumption := readTestAssumption(t)
+	req := NewAudioDBRequest("set_entry", &entity.AlbumEntry{ID: 1, Path: "a"})
+	require.NoError(t, req.ImportAssumption(assumption))
+
+	exported, err := asResponse(t, req).ExportAssumption()
+	require.NoError(t, err)
+	assert.JSONEq(t, releaseJSON(t, assumption.Release), releaseJSON(t, exported.Release))
+	assert.Equal(t, assumption.Actors, exported.Actors)
+	require.Len(t, exported.Pictures, len(assumption.Pictures))
+	for i, pict := range assumption.Pictures {
+		assert.Equal(t, pict.PictType, exported.Pictures[i].PictType)
+		assert.Equal(t, pict.MimeType, exported.Pictures[i].MimeType)
+		assert.Equal(t, pict.Width, exported.Pictures[i].Width)
+		assert.Equal(t, pict.Height, exported.Pictures[i].Height)
+		assert.Equal(t, pict.Data, exported.Pictures[i].Data)
+	}
+
+	empty, err := (&AudioDBResponse{AudioDBRequest: &AudioDBRequest{}}).ExportAssumption()
+	require.NoError(t, err)
+	assert.Empty(t, empty.Actors)
+	assert.Empty(t, empty.Pictures)
+}
+
+func TestExportSuggestionSet(t *testing.T) {
+	assumption := readTestAssumption(t)
+	set := md.NewSuggestionSet()
+	suggestion := md.NewSuggestion()
+	suggestion.Release = assumption.Release
+	suggestion.Release.IDs["discogs"] = "1234"
+	suggestion.ServiceName = "discogs"
+	suggestion.SourceSimilarity = 0.75
+	set.Suggestions = append(set.Suggestions, suggestion)
+	set.Actors = md.ActorIDs{}
+	for name, ids := range assumption.Actors {
+		set.Actors[name] = ids
+	}
+	set.Actors["Floor Jansen"] = md.IDs{"discogs": "347932"}
+
+	req := NewAudioDBRequest("set_entry", &entity.AlbumEntry{ID: 1, Path: "a"})
+	require.NoError(t, req.ImportAssumption(assumption))
+	require.NoError(t, req.ImportSuggestions(set))
+	resp := asResponse(t, req)
+
+	exported, err := resp.ExportSuggestionSet()
+	require.NoError(t, err)
+	require.Len(t, exported.Suggestions, 1)
+	assert.Equal(t, "discogs", exported.Suggestions[0].ServiceName)
+	assert.Equal(t, 0.75, exported.Suggestions[0].SourceSimilarity)
+	assert.JSONEq(t, releaseJSON(t, suggestion.Release), releaseJSON(t, exported.Suggestions[0].Release))
+	assert.Equal(t, set.Actors, exported.Actors)
+
+	// акторы предложений не попадают в метаданные каталога
+	album, err := resp.ExportAssumption()
+	require.NoError(t, err)
+	assert.Equal(t, assumption.Actors, album.Actors)
+
+	// очистка предложений сохраняет акторов каталога
+	req.clearSuggestionsMetadata()
+	exported, err = asResponse(t, req).ExportSuggestionSet()
+	require.NoError(t, err)
+	assert.Empty(t, exported.Suggestions)
+	assert.Empty(t, exported.Actors)
+	album, err = asResponse(t, req).ExportAssumption()
+	require.NoError(t, err)
+	assert.Equal(t, assumption.Actors, album.Actors)
+}
+
+func TestClearActorWithMask(t *testing.T) {
+	both := entity.AlbumEntryEntity | entity.SuggestionEntity
+	req := NewAudioDBRequest("set_entry", &entity.AlbumEntry{ID: 1, Path: "a"})
+	req.Actors = []*entity.Actor{
+		{Name: "Album", EntityMask: entity.AlbumEntryEntity},
+		{Name: "Suggestion", EntityMask: entity.SuggestionEntity},
+		{Name: "Both", EntityMask: entity.SuggestionEntity}}
+
+	// слияние добавляет признак к существующему актору
+	req.mergeActors(md.ActorIDs{"Both": md.IDs{}, "Album": md.IDs{}}, entity.AlbumEntryEntity)
+	assert.Equal(t, entity.AlbumEntryEntity, actorMask(req.Actors, "Album"))
+	assert.Equal(t, both, actorMask(req.Actors, "Both"))
+
+	// акторы без снимаемого признака остаются без изменений
+	req.clearActorWithMask(entity.SuggestionEntity)
+	require.Len(t, req.Actors, 2)
+	assert.Equal(t, entity.AlbumEntryEntity, actorMask(req.Actors, "Album"))
+	assert.Equal(t, entity.EntityMask(0), actorMask(req.Actors, "Suggestion"))
+	assert.Equal(t, entity.AlbumEntryEntity, actorMask(req.Actors, "Both"))
+
+	req.clearActorWithMask(entity.SuggestionEntity)
+	assert.Len(t, req.Actors, 2)
+	assert.Equal(t, entity.AlbumEntryEntity, actorMask(req.Actors, "Album"))
+
+	req.clearActorWithMask(entity.AlbumEntryEntity)
+	assert.Empty(t, req.Actors)
+}
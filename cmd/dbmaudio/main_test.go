@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/ytsiuryn/ds-audiodbm"
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+	"github.com/ytsiuryn/ds-audiodbm/entity/sqlitestore"
+)
+
+// Выполняет команду и возвращает ее вывод.
+func runCmd(name string, args ...string) (string, error) {
+	var buf bytes.Buffer
+	stdout = &buf
+	defer func() { stdout = os.Stdout }()
+	err := commands[name].run(args)
+	return buf.String(), err
+}
+
+// Выполняет команду, завершающуюся успешно, и разбирает ее ответ.
+func runEntryCmd(t *testing.T, name string, args ...string) *dbm.AudioDBRequest {
+	out, err := runCmd(name, args...)
+	require.NoError(t, err, name)
+	var answ dbm.AudioDBRequest
+	require.NoError(t, json.Unmarshal([]byte(out), &answ), name)
+	return &answ
+}
+
+func TestAdminCommands(t *testing.T) {
+	dir := t.TempDir()
+	dbURL := sqlitestore.URLScheme + filepath.Join(dir, "test.db")
+	db := "-db=" + dbURL
+
+	out, err := runCmd("migrate", db)
+	require.NoError(t, err)
+	assert.Contains(t, out, "schema version")
+
+	m := newDbm(dbURL)
+	_, err = m.Execute(dbm.NewAudioDBRequest("set_entry", &entity.AlbumEntry{
+		Path: "a", Json: []byte(`{"title":"Remagine"}`)}))
+	m.Close()
+	require.NoError(t, err)
+
+	answ := runEntryCmd(t, "get", db, "a")
+	assert.Equal(t, "a", answ.Entry.Path)
+	id := answ.Entry.ID
+
+	answ = runEntryCmd(t, "rename", db, "a", "b")
+	assert.Equal(t, "b", answ.NewPath)
+
+	answ = runEntryCmd(t, "list", db, "-prefix=b")
+	require.Len(t, answ.Entries, 1)
+	assert.Equal(t, id, answ.Entries[0].ID)
+
+	answ = runEntryCmd(t, "finalyze", db, "b")
+	assert.Equal(t, entity.StatusFinalyzed, answ.Entry.Status)
+	_, err = runCmd("finalyze", db, "b")
+	assert.ErrorIs(t, err, dbm.ErrAlreadyFinalyzed)
+
+	out, err = runCmd("export", db, "b")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"title":"Remagine"}`, out)
+	file := filepath.Join(dir, "release.json")
+	_, err = runCmd("export", db, "-o", file, "b")
+	require.NoError(t, err)
+	data, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	assert.JSONEq(t, out, string(data))
+
+	runEntryCmd(t, "delete", db, "b")
+	_, err = runCmd("get", db, "b")
+	assert.ErrorIs(t, err, entity.ErrNotFound)
+}
+
+func TestAdminCommandsUsage(t *testing.T) {
+	db := "-db=" + sqlitestore.URLScheme + filepath.Join(t.TempDir(), "test.db")
+	for _, args := range [][]string{
+		{"get", db},
+		{"get", db, "a", "b"},
+		{"rename", db, "a"},
+		{"list", db, "x"},
+		{"finalyze", "-db=", "a"},
+	} {
+		_, err := runCmd(args[0], args[1:]...)
+		assert.Equal(t, errUsage, err, args)
+	}
+}
+
+func TestEntryArg(t *testing.T) {
+	assert.Equal(t, &entity.AlbumEntry{ID: 12}, entryArg("12"))
+	assert.Equal(t, &entity.AlbumEntry{Path: "12a"}, entryArg("12a"))
+}
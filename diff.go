@@ -0,0 +1,137 @@
+package dbm
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+)
+
+// Операции изменения JSON-документа.
+const (
+	DiffAdd     = "add"
+	DiffRemove  = "remove"
+	DiffReplace = "replace"
+)
+
+// JSONChange описывает изменение значения в документе Entry.
+// Path задается в формате JSON Pointer (RFC 6901) относительно Entry, например,
+// "/status" или "/json/tracks/0/title".
+type JSONChange struct {
+	Path string          `json:"path"`
+	Op   string          `json:"op"`
+	Old  json.RawMessage `json:"old,omitempty"`
+	New  json.RawMessage `json:"new,omitempty"`
+}
+
+// diffRevisions возвращает изменения, переводящие состояние Entry `from` в `to`.
+func diffRevisions(from, to *entity.EntryRevision) ([]*JSONChange, error) {
+	var ret []*JSONChange
+	if from.Path != to.Path {
+		ret = append(ret, replaceChange("/path", from.Path, to.Path))
+	}
+	if from.Status != to.Status {
+		ret = append(ret, replaceChange("/status", from.Status, to.Status))
+	}
+	if !from.LastModified.Equal(to.LastModified) {
+		ret = append(ret, replaceChange("/last_modified",
+			from.LastModified.Format(time.RFC3339Nano), to.LastModified.Format(time.RFC3339Nano)))
+	}
+	changes, err := diffJSON(from.Json, to.Json)
+	if err != nil {
+		return nil, err
+	}
+	for _, change := range changes {
+		change.Path = "/json" + change.Path
+	}
+	return append(ret, changes...), nil
+}
+
+// diffJSON возвращает изменения, переводящие JSON-документ `a` в `b`.
+func diffJSON(a, b []byte) ([]*JSONChange, error) {
+	var va, vb interface{}
+	if len(a) > 0 {
+		if err := json.Unmarshal(a, &va); err != nil {
+			return nil, err
+		}
+	}
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, &vb); err != nil {
+			return nil, err
+		}
+	}
+	var ret []*JSONChange
+	diffValues("", va, vb, &ret)
+	return ret, nil
+}
+
+func diffValues(path string, a, b interface{}, out *[]*JSONChange) {
+	switch {
+	case reflect.DeepEqual(a, b):
+		return
+	case a == nil:
+		*out = append(*out, &JSONChange{Path: path, Op: DiffAdd, New: rawJSON(b)})
+		return
+	case b == nil:
+		*out = append(*out, &JSONChange{Path: path, Op: DiffRemove, Old: rawJSON(a)})
+		return
+	}
+
+	switch ma := a.(type) {
+	case map[string]interface{}:
+		mb, ok := b.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(ma)+len(mb))
+		for k := range ma {
+			keys = append(keys, k)
+		}
+		for k := range mb {
+			if _, ok := ma[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			diffValues(path+"/"+escapePointer(k), ma[k], mb[k], out)
+		}
+		return
+	case []interface{}:
+		sb, ok := b.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < len(ma) || i < len(sb); i++ {
+			var ea, eb interface{}
+			if i < len(ma) {
+				ea = ma[i]
+			}
+			if i < len(sb) {
+				eb = sb[i]
+			}
+			diffValues(path+"/"+strconv.Itoa(i), ea, eb, out)
+		}
+		return
+	}
+
+	*out = append(*out, &JSONChange{Path: path, Op: DiffReplace, Old: rawJSON(a), New: rawJSON(b)})
+}
+
+func replaceChange(path string, old, new interface{}) *JSONChange {
+	return &JSONChange{Path: path, Op: DiffReplace, Old: rawJSON(old), New: rawJSON(new)}
+}
+
+func rawJSON(v interface{}) json.RawMessage {
+	data, _ := json.Marshal(v)
+	return data
+}
+
+// Экранирует символы "~" и "/" в имени ключа для JSON Pointer.
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
@@ -0,0 +1,33 @@
+package dbm
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+)
+
+func TestDiffRevisions(t *testing.T) {
+	from := &entity.EntryRevision{
+		Path:   "test",
+		Status: "without_mandatory_tags",
+		Json:   []byte(`{"title":"Remagine","ids":{"a/b":"1"},"tracks":[{"title":"Enter"}]}`)}
+	to := &entity.EntryRevision{
+		Path:   "test",
+		Status: "with_mandatory_tags",
+		Json:   []byte(`{"title":"Remagine","year":2005,"tracks":[{"title":"ENTER"},{"title":"Come"}]}`)}
+
+	changes, err := diffRevisions(from, to)
+	require.NoError(t, err)
+	data, err := json.Marshal(changes)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"path":"/status","op":"replace","old":"without_mandatory_tags","new":"with_mandatory_tags"},
+		{"path":"/json/ids","op":"remove","old":{"a/b":"1"}},
+		{"path":"/json/tracks/0/title","op":"replace","old":"Enter","new":"ENTER"},
+		{"path":"/json/tracks/1","op":"add","new":{"title":"Come"}},
+		{"path":"/json/year","op":"add","new":2005}]`, string(data))
+}
@@ -0,0 +1,147 @@
+package dbm
+
+import (
+	"context"
+	"sync"
+)
+
+// Ключ задач, затрагивающих множество записей (перемещение дерева каталогов, сверка
+// с файловой системой). Такие задачи выполняются не одновременно с другими задачами.
+const exclusiveKey = "*"
+
+// dispatcher выполняет задачи параллельно с ограничением числа одновременно
+// обрабатываемых задач.
+// Задачи с одинаковым непустым ключом выполняются строго в порядке поступления,
+// задачи с ключом exclusiveKey - после завершения выполняемых задач и до начала
+// выполнения следующих.
+// Диспетчер общий для всех транспортов сервиса, поэтому ожидание завершения задач
+// допускает одновременную постановку новых задач.
+type dispatcher struct {
+	mu      sync.Mutex
+	queues  map[string][]func()
+	sem     chan struct{}
+	pending int
+	idle    *sync.Cond
+	gate    sync.RWMutex
+}
+
+// newDispatcher создает диспетчер с ограничением `limit` на число задач в обработке.
+func newDispatcher(limit int) *dispatcher {
+	if limit < 1 {
+		limit = 1
+	}
+	d := &dispatcher{
+		queues: map[string][]func(){},
+		sem:    make(chan struct{}, limit)}
+	d.idle = sync.NewCond(&d.mu)
+	return d
+}
+
+// Dispatch ставит задачу в обработку.
+// Если лимит задач в обработке исчерпан, вызов блокируется до освобождения места.
+func (d *dispatcher) Dispatch(key string, task func()) {
+	d.dispatch(context.Background(), key, task)
+}
+
+// Ставит задачу в обработку, ожидая освобождения места не дольше срока действия `ctx`.
+func (d *dispatcher) dispatch(ctx context.Context, key string, task func()) error {
+	select {
+	case d.sem <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	d.mu.Lock()
+	d.pending++
+	if key == "" || key == exclusiveKey {
+		d.mu.Unlock()
+		go d.run(key, task)
+		return nil
+	}
+
+	if queue, busy := d.queues[key]; busy {
+		d.queues[key] = append(queue, task)
+		d.mu.Unlock()
+		return nil
+	}
+	d.queues[key] = nil
+	d.mu.Unlock()
+
+	go d.run(key, task)
+	return nil
+}
+
+// Do выполняет задачу в порядке очереди ключа `key` и ожидает ее завершения.
+func (d *dispatcher) Do(key string, task func()) {
+	_ = d.DoContext(context.Background(), key, task)
+}
+
+// DoContext выполняет задачу в порядке очереди ключа `key` и ожидает ее завершения
+// не дольше срока действия `ctx`. Если срок действия истек до начала выполнения задачи,
+// задача не выполняется. Возвращает ошибку контекста, если завершение задачи не дождались;
+// в этом случае вызывающий код не должен использовать результаты задачи.
+func (d *dispatcher) DoContext(ctx context.Context, key string, task func()) error {
+	done := make(chan struct{})
+	var ran bool
+	err := d.dispatch(ctx, key, func() {
+		defer close(done)
+		if ctx.Err() != nil {
+			return
+		}
+		ran = true
+		task()
+	})
+	if err != nil {
+		return err
+	}
+	select {
+	case <-done:
+		if !ran {
+			return ctx.Err()
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Wait ожидает завершения всех поставленных в обработку задач.
+func (d *dispatcher) Wait() {
+	d.mu.Lock()
+	for d.pending > 0 {
+		d.idle.Wait()
+	}
+	d.mu.Unlock()
+}
+
+// Выполняет задачу и последовательно все задачи, накопившиеся в очереди того же ключа.
+func (d *dispatcher) run(key string, task func()) {
+	for {
+		if key == exclusiveKey {
+			d.gate.Lock()
+			task()
+			d.gate.Unlock()
+		} else {
+			d.gate.RLock()
+			task()
+			d.gate.RUnlock()
+		}
+		<-d.sem
+
+		d.mu.Lock()
+		if d.pending--; d.pending == 0 {
+			d.idle.Broadcast()
+		}
+		if key == "" || key == exclusiveKey {
+			d.mu.Unlock()
+			return
+		}
+		queue := d.queues[key]
+		if len(queue) == 0 {
+			delete(d.queues, key)
+			d.mu.Unlock()
+			return
+		}
+		task, d.queues[key] = queue[0], queue[1:]
+		d.mu.Unlock()
+	}
+}
@@ -0,0 +1,122 @@
+package dbm
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+)
+
+func TestDispatcherKeyOrder(t *testing.T) {
+	disp := newDispatcher(4)
+	var mu sync.Mutex
+	var order []int
+	for i := 0; i < 10; i++ {
+		i := i
+		disp.Dispatch("id:1", func() {
+			time.Sleep(time.Millisecond)
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+		})
+	}
+	disp.Wait()
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, order)
+}
+
+func TestDispatcherLimit(t *testing.T) {
+	disp := newDispatcher(2)
+	var running, maxRunning int32
+	for i := 0; i < 8; i++ {
+		disp.Dispatch("", func() {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				max := atomic.LoadInt32(&maxRunning)
+				if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+		})
+	}
+	disp.Wait()
+	assert.LessOrEqual(t, maxRunning, int32(2))
+	assert.Equal(t, int32(2), maxRunning)
+}
+
+func TestDispatcherWaitWhileDispatching(t *testing.T) {
+	disp := newDispatcher(2)
+	var done int32
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				disp.Do("", func() { atomic.AddInt32(&done, 1) })
+			}
+		}()
+	}
+	for i := 0; i < 50; i++ {
+		disp.Wait()
+	}
+	wg.Wait()
+	disp.Wait()
+	assert.Equal(t, int32(200), atomic.LoadInt32(&done))
+}
+
+func TestDispatcherExclusive(t *testing.T) {
+	disp := newDispatcher(4)
+	var running, exclusiveRunning, overlaps int32
+	task := func(exclusive bool) func() {
+		return func() {
+			n := atomic.AddInt32(&running, 1)
+			if exclusive {
+				atomic.StoreInt32(&exclusiveRunning, 1)
+			}
+			if (exclusive && n != 1) || (!exclusive && atomic.LoadInt32(&exclusiveRunning) != 0) {
+				atomic.AddInt32(&overlaps, 1)
+			}
+			time.Sleep(time.Millisecond)
+			if exclusive {
+				atomic.StoreInt32(&exclusiveRunning, 0)
+			}
+			atomic.AddInt32(&running, -1)
+		}
+	}
+	for i := 0; i < 20; i++ {
+		disp.Dispatch("id:"+strconv.Itoa(i%3), task(false))
+		if i%5 == 0 {
+			disp.Dispatch(exclusiveKey, task(true))
+		}
+	}
+	disp.Wait()
+	assert.Zero(t, overlaps)
+}
+
+func TestEntryKey(t *testing.T) {
+	store := entity.NewMemStore()
+	m := New("", WithStore(store))
+	defer m.Close()
+	ctx := context.Background()
+	txctx, tx, err := store.Begin(ctx)
+	require.NoError(t, err)
+	entry := &entity.AlbumEntry{Path: "a", Status: entity.StatusWithoutMandatoryTags}
+	require.NoError(t, store.CreateEntry(txctx, entry))
+	require.NoError(t, tx.Commit(ctx))
+
+	byID := NewAudioDBRequest("set_entry", &entity.AlbumEntry{ID: entry.ID})
+	byPath := NewAudioDBRequest("get_entry", &entity.AlbumEntry{Path: "a"})
+	assert.Equal(t, m.entryKey(byID), m.entryKey(byPath))
+	assert.Equal(t, "path:b", m.entryKey(NewAudioDBRequest("set_entry", &entity.AlbumEntry{Path: "b"})))
+	assert.Equal(t, exclusiveKey, m.entryKey(NewAudioDBRequest("move_tree", nil)))
+	assert.Empty(t, m.entryKey(NewAudioDBRequest("list_entries", nil)))
+}
@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// AcceptedSuggestion описывает предложение, принятое в качестве релиза каталога.
+type AcceptedSuggestion struct {
+	EntryID     int       `sql:"entry_id" json:"entry_id"`
+	ExtDB       string    `sql:"ext_db" json:"ext_db"`
+	ExtID       string    `sql:"ext_id" json:"ext_id"`
+	MergePolicy string    `sql:"merge_policy" json:"merge_policy,omitempty"`
+	AcceptedAt  time.Time `sql:"accepted_at" json:"accepted_at"`
+}
+
+// Save записывает объект в БД, заменяя ранее принятое для каталога предложение.
+func (as *AcceptedSuggestion) Save(ctx context.Context) error {
+	err := InsertFullRec(
+		ctx,
+		`INSERT INTO audio.accepted_suggestion (entry_id,ext_db,ext_id,merge_policy,accepted_at)
+		VALUES($1,$2,$3,$4,$5)
+		ON CONFLICT (entry_id) DO UPDATE SET ext_db=EXCLUDED.ext_db,ext_id=EXCLUDED.ext_id,
+		merge_policy=EXCLUDED.merge_policy,accepted_at=EXCLUDED.accepted_at`,
+		as.EntryID, as.ExtDB, as.ExtID, as.MergePolicy, as.AcceptedAt)
+	if err != nil {
+		err = errors.Wrapf(
+			err, "AcceptedSuggestion.Save() failed: entry_id=%d, ext_db=%s, ext_id=%s",
+			as.EntryID, as.ExtDB, as.ExtID)
+	}
+	return err
+}
+
+// Get ищет объект в БД по ID записи каталога.
+func (as *AcceptedSuggestion) Get(ctx context.Context) error {
+	qry := `SELECT ext_db,ext_id,merge_policy,accepted_at FROM audio.accepted_suggestion
+	WHERE entry_id=$1 LIMIT 1`
+	row, err := Get(ctx, qry, as.EntryID)
+	if err != nil {
+		return errors.Wrap(err, "AcceptedSuggestion.Get() select failed")
+	}
+	err = row.Scan(&as.ExtDB, &as.ExtID, &as.MergePolicy, &as.AcceptedAt)
+	if err != nil {
+		err = errors.Wrapf(err, "AcceptedSuggestion.Get() scan failed: entry_id=%d", as.EntryID)
+	}
+	return err
+}
+
+// DeleteEntryAcceptedSuggestion удаляет сведения о принятом для Entry предложении.
+func DeleteEntryAcceptedSuggestion(ctx context.Context, entryID int) error {
+	err := Delete(ctx, "DELETE FROM audio.accepted_suggestion WHERE entry_id=$1", entryID)
+	if err != nil {
+		err = errors.Wrap(err, "DeleteEntryAcceptedSuggestion() failed")
+	}
+	return err
+}
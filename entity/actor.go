@@ -37,6 +37,23 @@ func (a *Actor) Create(ctx context.Context) (err error) {
 	return
 }
 
+// Update обновляет идентификаторы и маску принадлежности актора.
+func (a *Actor) Update(ctx context.Context) error {
+	tx, err := Tx(ctx)
+	if err != nil {
+		return errors.Wrap(err, "Actor.Update() failed")
+	}
+	_, err = tx.Exec(
+		ctx,
+		`UPDATE audio.actor SET ids=$1,entity_mask=$2 WHERE entry_id=$3 AND name=$4`,
+		a.IDs, a.EntityMask, a.EntryID, a.Name)
+	if err != nil {
+		err = errors.Wrapf(
+			err, "Actor.Update() failed: entry_id=%d, name=%s", a.EntryID, a.Name)
+	}
+	return err
+}
+
 // Delete удаляет объект в БД по ID записи.
 func (a *Actor) Delete(ctx context.Context) (err error) {
 	err = Delete(
@@ -67,9 +84,9 @@ func (a *Actor) Get(ctx context.Context) error {
 
 // EntryActors возвращает список акторов для указанного Entry.
 func EntryActors(ctx context.Context, entryID int) ([]*Actor, error) {
-	db := ctx.Value("db").(*pgx.Conn)
-	if db == nil {
-		return nil, errors.Wrap(ErrConnectionInContext, "EntryActors() failed")
+	db, err := Conn(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "EntryActors() failed")
 	}
 
 	rows, err := db.Query(ctx, "SELECT * FROM audio.actor WHERE entry_id=$1", entryID)
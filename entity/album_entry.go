@@ -10,13 +10,24 @@ import (
 	"github.com/pkg/errors"
 )
 
+// ErrVersionConflict возвращается при изменении записи, которая была изменена другим
+// клиентом после ее чтения.
+var ErrVersionConflict = errors.New("entry was changed since it was read")
+
 // AlbumEntry описывает каталог репозитория с релизом.
+// Version увеличивается при каждом изменении записи и используется для контроля
+// одновременного редактирования: ненулевое значение Version при вызове Update()
+// должно совпадать с версией записи в БД.
+// StaleSince устанавливается при сверке с файловой системой для записей, каталоги
+// которых не найдены.
 type AlbumEntry struct {
-	ID           int       `json:"id,omitempty"`
-	Path         string    `json:"path,omitempty"`
-	Json         []byte    `json:"json,omitempty"`
-	Status       string    `json:"status,omitempty"` // тип audio.entry_status
-	LastModified time.Time `json:"last_modified"`
+	ID           int        `json:"id,omitempty"`
+	Path         string     `json:"path,omitempty"`
+	Json         []byte     `json:"json,omitempty"`
+	Status       string     `json:"status,omitempty"` // тип audio.entry_status
+	LastModified time.Time  `json:"last_modified"`
+	Version      int        `json:"version,omitempty"`
+	StaleSince   *time.Time `json:"stale_since,omitempty"`
 }
 
 // Create записывает объект в БД.
@@ -27,22 +38,37 @@ func (ent *AlbumEntry) Create(ctx context.Context) (err error) {
 		RETURNING id`,
 		ent.Path, ent.Json, ent.Status, ent.LastModified)
 	if err != nil {
-		err = errors.Wrapf(err, "AlbumEntry.Create() failed: path=%s", ent.Path)
+		return errors.Wrapf(err, "AlbumEntry.Create() failed: path=%s", ent.Path)
 	}
-	return err
+	ent.Version = 1
+	return nil
 }
 
-// Update обновляет данные для записи с указанным ID.
+// Update обновляет данные для записи с указанным ID и увеличивает ее версию.
+// Если версия записи в БД отличается от ненулевого значения ent.Version,
+// возвращается ошибка ErrVersionConflict.
 func (ent *AlbumEntry) Update(ctx context.Context) error {
-	tx := ctx.Value("tx").(pgx.Tx)
-	if tx == nil {
-		return errors.Wrapf(
-			ErrConnectionInContext, "AlbumEntry.Update() failed: path=%s", ent.Path)
+	tx, err := Tx(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "AlbumEntry.Update() failed: path=%s", ent.Path)
 	}
-	_, err := tx.Exec(
+	expected := ent.Version
+	err = tx.QueryRow(
 		ctx,
-		"UPDATE audio.album_entry SET path=$1,json=$2,status=$3,last_modified=$4 WHERE id=$5",
-		ent.Path, ent.Json, ent.Status, ent.LastModified, ent.ID)
+		`UPDATE audio.album_entry SET path=$1,json=$2,status=$3,last_modified=$4,
+		version=version+1 WHERE id=$5 AND ($6=0 OR version=$6) RETURNING version`,
+		ent.Path, ent.Json, ent.Status, ent.LastModified, ent.ID, expected).Scan(&ent.Version)
+	if err == pgx.ErrNoRows && expected != 0 {
+		var current int
+		err = tx.QueryRow(
+			ctx, "SELECT version FROM audio.album_entry WHERE id=$1", ent.ID).Scan(&current)
+		if err == nil {
+			ent.Version = expected
+			return errors.Wrapf(ErrVersionConflict,
+				"AlbumEntry.Update() failed: id=%d, version=%d, current version=%d",
+				ent.ID, expected, current)
+		}
+	}
 	if err != nil {
 		err = errors.Wrapf(err, "AlbumEntry.Update() failed: id=%d", ent.ID)
 	}
@@ -80,16 +106,20 @@ func (ent *AlbumEntry) Get(ctx context.Context) (err error) {
 	if ent.ID != 0 {
 		row, err = Get(
 			ctx,
-			"SELECT * FROM audio.album_entry WHERE id=$1 LIMIT 1", ent.ID)
+			`SELECT id,path,json,status,last_modified,version,stale_since FROM audio.album_entry
+			WHERE id=$1 LIMIT 1`, ent.ID)
 	} else {
 		row, err = Get(
 			ctx,
-			"SELECT * FROM audio.album_entry WHERE path=$1 LIMIT 1", ent.Path)
+			`SELECT id,path,json,status,last_modified,version,stale_since FROM audio.album_entry
+			WHERE path=$1 LIMIT 1`, ent.Path)
 	}
 	if err != nil && err != pgx.ErrNoRows {
 		return errors.Wrapf(err, "AlbumEntry.Get() select failed: id=%d", ent.ID)
 	}
-	err = row.Scan(&ent.ID, &ent.Path, &ent.Json, &ent.Status, &ent.LastModified)
+	err = row.Scan(
+		&ent.ID, &ent.Path, &ent.Json, &ent.Status, &ent.LastModified, &ent.Version,
+		&ent.StaleSince)
 	if err != nil {
 		err = errors.Wrapf(err, "AlbumEntry.Get() scan failed: id=%d", ent.ID)
 	}
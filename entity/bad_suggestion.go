@@ -63,9 +63,9 @@ func (bs *BadSuggestion) Get(ctx context.Context) error {
 // EntryBadSuggestions возвращает список ID релизов, которые не следует использовать при поиске
 // новых предложений.
 func EntryBadSuggestions(ctx context.Context, entryID int) ([]*BadSuggestion, error) {
-	db := ctx.Value("db").(*pgx.Conn)
-	if db == nil {
-		return nil, errors.Wrap(ErrConnectionInContext, "EntryBadSuggestions() failed")
+	db, err := Conn(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "EntryBadSuggestions() failed")
 	}
 
 	rows, err := db.Query(ctx, "SELECT * FROM audio.bad_suggestion WHERE entry_id=$1", entryID)
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/pkg/errors"
 )
 
@@ -13,11 +15,68 @@ var (
 	ErrConnectionInContext = errors.New("could not get database connection pool from context")
 )
 
+// ConnType описывает перечисление типов соединений, хранимых в контексте.
+type ConnType string
+
+func (ct ConnType) String() string {
+	return string(ct)
+}
+
+// Допустимые типы соединений
+const (
+	NormalConnType      = ConnType("db")
+	TransactionConnType = ConnType("tx")
+)
+
+// Querier объединяет методы выполнения запросов, общие для пула соединений и транзакции.
+type Querier interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// WithPool возвращает контекст с пулом соединений к БД.
+func WithPool(ctx context.Context, pool *pgxpool.Pool) context.Context {
+	return context.WithValue(ctx, NormalConnType, pool)
+}
+
+// WithTx возвращает контекст с открытой транзакцией.
+func WithTx(ctx context.Context, tx pgx.Tx) context.Context {
+	return context.WithValue(ctx, TransactionConnType, tx)
+}
+
+// Pool возвращает пул соединений к БД из контекста.
+func Pool(ctx context.Context) (*pgxpool.Pool, error) {
+	pool, ok := ctx.Value(NormalConnType).(*pgxpool.Pool)
+	if !ok || pool == nil {
+		return nil, ErrConnectionInContext
+	}
+	return pool, nil
+}
+
+// Tx возвращает открытую транзакцию из контекста.
+func Tx(ctx context.Context) (pgx.Tx, error) {
+	tx, ok := ctx.Value(TransactionConnType).(pgx.Tx)
+	if !ok || tx == nil {
+		return nil, ErrConnectionInContext
+	}
+	return tx, nil
+}
+
+// Conn возвращает объект для чтения данных: открытую транзакцию, если она есть в контексте,
+// или пул соединений.
+func Conn(ctx context.Context) (Querier, error) {
+	if tx, err := Tx(ctx); err == nil {
+		return tx, nil
+	}
+	return Pool(ctx)
+}
+
 // Insert создает объект в БД и возвращает ID новой записи.
 func Insert(ctx context.Context, cmd string, args ...interface{}) (id int, err error) {
-	tx := ctx.Value("tx").(pgx.Tx)
-	if tx == nil {
-		return 0, ErrConnectionInContext
+	tx, err := Tx(ctx)
+	if err != nil {
+		return 0, err
 	}
 	if err = tx.QueryRow(ctx, cmd, args...).Scan(&id); err != nil {
 		return 0, errors.Wrapf(err, "Insert() error: %v", ArgsAsStrings(args))
@@ -27,9 +86,9 @@ func Insert(ctx context.Context, cmd string, args ...interface{}) (id int, err e
 
 // InsertFullRec - общая функция создания объекта в БД "с нуля".
 func InsertFullRec(ctx context.Context, cmd string, args ...interface{}) (err error) {
-	tx := ctx.Value("tx").(pgx.Tx)
-	if tx == nil {
-		return ErrConnectionInContext
+	tx, err := Tx(ctx)
+	if err != nil {
+		return err
 	}
 	if _, err = tx.Exec(ctx, cmd, args...); err != nil {
 		return errors.Wrapf(err, fmt.Sprint("InsertFullRec() error:", ArgsAsStrings(args)))
@@ -40,21 +99,21 @@ func InsertFullRec(ctx context.Context, cmd string, args ...interface{}) (err er
 // Delete - общая функция удаления объекта из таблицы tblName по первичному ключу,
 // представленному полями `pkFldValues`.
 func Delete(ctx context.Context, cmd string, pkFldValues ...interface{}) error {
-	tx := ctx.Value("tx").(pgx.Tx)
-	if tx == nil {
-		return ErrConnectionInContext
+	tx, err := Tx(ctx)
+	if err != nil {
+		return err
 	}
-	_, err := tx.Exec(ctx, cmd, pkFldValues...)
+	_, err = tx.Exec(ctx, cmd, pkFldValues...)
 	return err
 }
 
 // Get - общая функция для получения объекта в таблице tblName по первичному ключу,
 // представленному полями `pkFldValues`.
-// Если запись не найдена, на выходе pgx.Row = nil.
+// Если запись не найдена, ошибку pgx.ErrNoRows возвращает метод Scan() у pgx.Row.
 func Get(ctx context.Context, qry string, pkFldValues ...interface{}) (pgx.Row, error) {
-	db := ctx.Value("db").(*pgx.Conn)
-	if db == nil {
-		return nil, ErrConnectionInContext
+	db, err := Conn(ctx)
+	if err != nil {
+		return nil, err
 	}
 	return db.QueryRow(ctx, qry, pkFldValues...), nil
 }
@@ -64,12 +123,11 @@ func Get(ctx context.Context, qry string, pkFldValues ...interface{}) (pgx.Row,
 func ArgsAsStrings(args []interface{}) []string {
 	ret := make([]string, 0, len(args))
 	for _, v := range args {
-		fmt.Println(fmt.Sprintf("%T", v))
 		switch v.(type) {
 		case int8, int16, int32, int64, int:
-			ret = append(ret, fmt.Sprintf("%d", v.(int)))
+			ret = append(ret, fmt.Sprintf("%d", v))
 		case float32, float64:
-			ret = append(ret, fmt.Sprintf("%f", v.(float64)))
+			ret = append(ret, fmt.Sprintf("%f", v))
 		case []uint8:
 			ret = append(ret, "...long field skipped...")
 		default:
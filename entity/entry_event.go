@@ -0,0 +1,104 @@
+package entity
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Типы событий изменения Entry.
+const (
+	EntryCreated     = "entry_created"
+	EntryUpdated     = "entry_updated"
+	EntryDeleted     = "entry_deleted"
+	EntryFinalyzed   = "entry_finalyzed"
+	EntryRenamed     = "entry_renamed"
+	EntryUnfinalyzed = "entry_unfinalyzed"
+)
+
+// EntryEvent описывает событие изменения Entry.
+// События записываются в таблицу audio.event_outbox в транзакции изменения данных
+// и публикуются после ее фиксации.
+type EntryEvent struct {
+	ID        int       `json:"id"`
+	Type      string    `json:"type"`
+	Cmd       string    `json:"cmd"`
+	EntryID   int       `json:"entry_id"`
+	Path      string    `json:"path,omitempty"`
+	OldPath   string    `json:"old_path,omitempty"`
+	OldStatus string    `json:"old_status,omitempty"`
+	NewStatus string    `json:"new_status,omitempty"`
+	Changed   []string  `json:"changed,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Create записывает событие в таблицу audio.event_outbox.
+func (ev *EntryEvent) Create(ctx context.Context) (err error) {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return errors.Wrap(err, "EntryEvent.Create() failed")
+	}
+	ev.ID, err = Insert(
+		ctx,
+		`INSERT INTO audio.event_outbox (type,entry_id,payload,created_at) VALUES($1,$2,$3,$4)
+		RETURNING id`,
+		ev.Type, ev.EntryID, payload, ev.CreatedAt)
+	if err != nil {
+		err = errors.Wrapf(err, "EntryEvent.Create() failed: type=%s, entry_id=%d",
+			ev.Type, ev.EntryID)
+	}
+	return
+}
+
+// PendingEntryEvents возвращает до `limit` неопубликованных событий в порядке их создания.
+// Выбранные записи блокируются до конца транзакции контекста, что позволяет нескольким
+// экземплярам сервиса публиковать события без дублирования.
+func PendingEntryEvents(ctx context.Context, limit int) ([]*EntryEvent, error) {
+	tx, err := Tx(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "PendingEntryEvents() failed")
+	}
+
+	rows, err := tx.Query(
+		ctx,
+		`SELECT id,payload FROM audio.event_outbox WHERE published_at IS NULL
+		ORDER BY id LIMIT $1 FOR UPDATE SKIP LOCKED`,
+		limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "PendingEntryEvents() select failed")
+	}
+	defer rows.Close()
+
+	ret := []*EntryEvent{}
+	for rows.Next() {
+		var id int
+		var payload []byte
+		if err = rows.Scan(&id, &payload); err != nil {
+			return nil, errors.Wrap(err, "PendingEntryEvents() scan failed")
+		}
+		var ev EntryEvent
+		if err = json.Unmarshal(payload, &ev); err != nil {
+			return nil, errors.Wrapf(err, "PendingEntryEvents() payload decoding failed: id=%d", id)
+		}
+		ev.ID = id
+		ret = append(ret, &ev)
+	}
+
+	return ret, rows.Err()
+}
+
+// MarkPublished отмечает событие как опубликованное.
+func (ev *EntryEvent) MarkPublished(ctx context.Context) error {
+	tx, err := Tx(ctx)
+	if err != nil {
+		return errors.Wrap(err, "EntryEvent.MarkPublished() failed")
+	}
+	_, err = tx.Exec(
+		ctx, "UPDATE audio.event_outbox SET published_at=$1 WHERE id=$2", time.Now().UTC(), ev.ID)
+	if err != nil {
+		err = errors.Wrapf(err, "EntryEvent.MarkPublished() failed: id=%d", ev.ID)
+	}
+	return err
+}
@@ -0,0 +1,249 @@
+package entity
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Поля сортировки списка Entry.
+const (
+	SortByPath         = "path"
+	SortByID           = "id"
+	SortByLastModified = "last_modified"
+)
+
+// Ограничения на размер страницы списка Entry.
+const (
+	DefaultListLimit = 100
+	MaxListLimit     = 1000
+)
+
+// Ошибки формирования списка Entry.
+var (
+	ErrBadSortField = errors.New("unknown sort field")
+	ErrBadCursor    = errors.New("malformed cursor")
+)
+
+// EntryFilter описывает условия отбора, сортировки и постраничного вывода записей
+// audio.album_entry.
+type EntryFilter struct {
+	Status         string     `json:"status,omitempty"` // тип audio.entry_status
+	PathPrefix     string     `json:"path_prefix,omitempty"`
+	ModifiedAfter  *time.Time `json:"modified_after,omitempty"`
+	ModifiedBefore *time.Time `json:"modified_before,omitempty"`
+	HasSuggestions *bool      `json:"has_suggestions,omitempty"`
+	SortBy         string     `json:"sort_by,omitempty"`
+	Desc           bool       `json:"desc,omitempty"`
+	Limit          int        `json:"limit,omitempty"`
+	Cursor         string     `json:"cursor,omitempty"`
+}
+
+// EntryCursor описывает позицию последней записи страницы, закодированную в непрозрачный
+// для клиента курсор. Сортировка сохраняется в курсоре для контроля неизменности условий
+// между запросами.
+type EntryCursor struct {
+	SortBy string `json:"s"`
+	Desc   bool   `json:"d,omitempty"`
+	Value  string `json:"v,omitempty"`
+	ID     int    `json:"id"`
+
+	Modified time.Time `json:"-"` // разобранное значение Value для сортировки по last_modified
+}
+
+func (c *EntryCursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeEntryCursor(s string) (*EntryCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrBadCursor
+	}
+	var c EntryCursor
+	if err = json.Unmarshal(data, &c); err != nil {
+		return nil, ErrBadCursor
+	}
+	return &c, nil
+}
+
+// ListEntries возвращает страницу записей audio.album_entry, удовлетворяющих фильтру,
+// и курсор следующей страницы (пустой для последней страницы).
+// Данные релиза (поле Json) в выборку не включаются.
+func ListEntries(ctx context.Context, filter *EntryFilter) (_ []*AlbumEntry, next string, err error) {
+	db, err := Conn(ctx)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ListEntries() failed")
+	}
+
+	qry, args, err := filter.query()
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ListEntries() failed")
+	}
+
+	rows, err := db.Query(ctx, qry, args...)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ListEntries() select failed")
+	}
+	defer rows.Close()
+
+	ret := []*AlbumEntry{}
+	for rows.Next() {
+		var ent AlbumEntry
+		err = rows.Scan(&ent.ID, &ent.Path, &ent.Status, &ent.LastModified, &ent.Version)
+		if err != nil {
+			return nil, "", errors.Wrap(err, "ListEntries() scan failed")
+		}
+		ret = append(ret, &ent)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, "", errors.Wrap(err, "ListEntries() select failed")
+	}
+
+	if limit := filter.PageLimit(); len(ret) > limit {
+		ret = ret[:limit]
+		next = filter.NextCursor(ret[limit-1])
+	}
+
+	return ret, next, nil
+}
+
+// SortField возвращает поле сортировки списка.
+func (f *EntryFilter) SortField() string {
+	if f.SortBy == "" {
+		return SortByPath
+	}
+	return f.SortBy
+}
+
+// PageLimit возвращает размер страницы списка.
+func (f *EntryFilter) PageLimit() int {
+	switch {
+	case f.Limit <= 0:
+		return DefaultListLimit
+	case f.Limit > MaxListLimit:
+		return MaxListLimit
+	}
+	return f.Limit
+}
+
+// NextCursor возвращает курсор страницы, следующей за записью `ent`.
+func (f *EntryFilter) NextCursor(ent *AlbumEntry) string {
+	return f.cursorAfter(ent).encode()
+}
+
+func (f *EntryFilter) cursorAfter(ent *AlbumEntry) *EntryCursor {
+	c := EntryCursor{SortBy: f.SortField(), Desc: f.Desc, ID: ent.ID}
+	switch c.SortBy {
+	case SortByPath:
+		c.Value = ent.Path
+	case SortByLastModified:
+		c.Value = ent.LastModified.Format(time.RFC3339Nano)
+	}
+	return &c
+}
+
+// Position проверяет поле сортировки и возвращает позицию курсора страницы или nil
+// для первой страницы.
+func (f *EntryFilter) Position() (*EntryCursor, error) {
+	sortBy := f.SortField()
+	switch sortBy {
+	case SortByPath, SortByID, SortByLastModified:
+	default:
+		return nil, errors.Wrap(ErrBadSortField, sortBy)
+	}
+	if f.Cursor == "" {
+		return nil, nil
+	}
+	c, err := decodeEntryCursor(f.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if c.SortBy != sortBy || c.Desc != f.Desc {
+		return nil, errors.Wrap(ErrBadCursor, "sort order differs from cursor")
+	}
+	if sortBy == SortByLastModified {
+		if c.Modified, err = time.Parse(time.RFC3339Nano, c.Value); err != nil {
+			return nil, ErrBadCursor
+		}
+	}
+	return c, nil
+}
+
+// Формирует текст запроса и его параметры.
+// Для стабильного постраничного вывода к полю сортировки всегда добавляется id.
+func (f *EntryFilter) query() (string, []interface{}, error) {
+	var where []string
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if f.Status != "" {
+		where = append(where, "status="+arg(f.Status))
+	}
+	if f.PathPrefix != "" {
+		where = append(where, "path LIKE "+arg(EscapeLike(f.PathPrefix)+"%"))
+	}
+	if f.ModifiedAfter != nil {
+		where = append(where, "last_modified>="+arg(f.ModifiedAfter.UTC()))
+	}
+	if f.ModifiedBefore != nil {
+		where = append(where, "last_modified<"+arg(f.ModifiedBefore.UTC()))
+	}
+	if f.HasSuggestions != nil {
+		cond := "EXISTS (SELECT 1 FROM audio.suggestion s WHERE s.entry_id=e.id)"
+		if !*f.HasSuggestions {
+			cond = "NOT " + cond
+		}
+		where = append(where, cond)
+	}
+
+	sortBy := f.SortField()
+	c, err := f.Position()
+	if err != nil {
+		return "", nil, err
+	}
+
+	op, dir := ">", "ASC"
+	if f.Desc {
+		op, dir = "<", "DESC"
+	}
+
+	if c != nil {
+		switch sortBy {
+		case SortByID:
+			where = append(where, "id"+op+arg(c.ID))
+		case SortByPath:
+			where = append(where, fmt.Sprintf("(path,id)%s(%s,%s)", op, arg(c.Value), arg(c.ID)))
+		case SortByLastModified:
+			where = append(where,
+				fmt.Sprintf("(last_modified,id)%s(%s,%s)", op, arg(c.Modified), arg(c.ID)))
+		}
+	}
+
+	qry := "SELECT id,path,status,last_modified,version FROM audio.album_entry e"
+	if len(where) > 0 {
+		qry += " WHERE " + strings.Join(where, " AND ")
+	}
+	if sortBy == SortByID {
+		qry += " ORDER BY id " + dir
+	} else {
+		qry += fmt.Sprintf(" ORDER BY %s %s,id %s", sortBy, dir, dir)
+	}
+	qry += " LIMIT " + arg(f.PageLimit()+1)
+
+	return qry, args, nil
+}
+
+// EscapeLike экранирует спецсимволы шаблона LIKE.
+func EscapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntryFilterQuery(t *testing.T) {
+	hasSuggestions := true
+	f := &EntryFilter{
+		Status:         "with_mandatory_tags",
+		PathPrefix:     "Pink_Floyd/",
+		HasSuggestions: &hasSuggestions,
+		Limit:          10}
+	qry, args, err := f.query()
+	require.NoError(t, err)
+	assert.Equal(t,
+		"SELECT id,path,status,last_modified,version FROM audio.album_entry e"+
+			" WHERE status=$1 AND path LIKE $2"+
+			" AND EXISTS (SELECT 1 FROM audio.suggestion s WHERE s.entry_id=e.id)"+
+			" ORDER BY path ASC,id ASC LIMIT $3",
+		qry)
+	assert.Equal(t, []interface{}{"with_mandatory_tags", `Pink\_Floyd/%`, 11}, args)
+
+	f.SortBy = "size"
+	_, _, err = f.query()
+	assert.Error(t, err)
+}
+
+func TestEntryFilterCursor(t *testing.T) {
+	f := &EntryFilter{SortBy: SortByLastModified, Desc: true}
+	ent := &AlbumEntry{ID: 7, LastModified: time.Date(2021, 6, 4, 13, 55, 59, 0, time.UTC)}
+	f.Cursor = f.cursorAfter(ent).encode()
+
+	qry, args, err := f.query()
+	require.NoError(t, err)
+	assert.Contains(t, qry, "(last_modified,id)<($1,$2) ORDER BY last_modified DESC,id DESC")
+	assert.Equal(t, ent.LastModified, args[0])
+	assert.Equal(t, 7, args[1])
+
+	f.Desc = false
+	_, _, err = f.query()
+	assert.Error(t, err)
+
+	f.Cursor = "???"
+	_, _, err = f.query()
+	assert.ErrorIs(t, err, ErrBadCursor)
+}
@@ -0,0 +1,150 @@
+package entity
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+)
+
+// EntryRevision описывает сохраненное состояние Entry до его изменения.
+// Наряду с полями Entry ревизия содержит снимок акторов каталога и сведений о принятом
+// предложении. Для ревизий, сохраненных без снимка, Actors равно nil.
+type EntryRevision struct {
+	ID           int                 `json:"id"`
+	EntryID      int                 `sql:"entry_id" json:"entry_id"`
+	Path         string              `json:"path"`
+	Json         []byte              `json:"json,omitempty"`
+	Status       string              `json:"status,omitempty"` // тип audio.entry_status
+	LastModified time.Time           `sql:"last_modified" json:"last_modified"`
+	Actors       []*Actor            `json:"actors,omitempty"`
+	Accepted     *AcceptedSuggestion `json:"accepted,omitempty"`
+	Cmd          string              `json:"cmd"`
+	CreatedAt    time.Time           `sql:"created_at" json:"created_at"`
+}
+
+// MarshalRefs возвращает снимок акторов и принятого предложения ревизии в формате JSON
+// для записи в БД. При отсутствии принятого предложения `accepted` равно nil.
+func (r *EntryRevision) MarshalRefs() (actors, accepted []byte, err error) {
+	list := r.Actors
+	if list == nil {
+		list = []*Actor{}
+	}
+	if actors, err = json.Marshal(list); err != nil {
+		return nil, nil, errors.Wrap(err, "EntryRevision.MarshalRefs() failed")
+	}
+	if r.Accepted != nil {
+		if accepted, err = json.Marshal(r.Accepted); err != nil {
+			return nil, nil, errors.Wrap(err, "EntryRevision.MarshalRefs() failed")
+		}
+	}
+	return
+}
+
+// UnmarshalRefs восстанавливает снимок акторов и принятого предложения ревизии,
+// прочитанный из БД. Пустое значение `actors` означает ревизию без снимка.
+func (r *EntryRevision) UnmarshalRefs(actors, accepted []byte) error {
+	r.Actors, r.Accepted = nil, nil
+	if len(actors) > 0 {
+		if err := json.Unmarshal(actors, &r.Actors); err != nil {
+			return errors.Wrap(err, "EntryRevision.UnmarshalRefs() failed")
+		}
+	}
+	if len(accepted) > 0 {
+		if err := json.Unmarshal(accepted, &r.Accepted); err != nil {
+			return errors.Wrap(err, "EntryRevision.UnmarshalRefs() failed")
+		}
+	}
+	return nil
+}
+
+// SaveEntryRevision сохраняет текущее состояние Entry, его акторов и принятого предложения
+// перед изменением Entry командой `cmd`.
+func SaveEntryRevision(ctx context.Context, entryID int, cmd string) (err error) {
+	rev := &EntryRevision{EntryID: entryID}
+	if rev.Actors, err = EntryActors(ctx, entryID); err != nil {
+		return errors.Wrapf(err, "SaveEntryRevision() failed: entry_id=%d", entryID)
+	}
+	accepted := &AcceptedSuggestion{EntryID: entryID}
+	if err = accepted.Get(ctx); err == nil {
+		rev.Accepted = accepted
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return errors.Wrapf(err, "SaveEntryRevision() failed: entry_id=%d", entryID)
+	}
+	actors, acceptedData, err := rev.MarshalRefs()
+	if err != nil {
+		return err
+	}
+	_, err = Insert(
+		ctx,
+		`INSERT INTO audio.album_entry_revision
+		(entry_id,path,json,status,last_modified,actors,accepted,cmd,created_at)
+		SELECT id,path,json,status,last_modified,$4,$5,$2,$3 FROM audio.album_entry WHERE id=$1
+		RETURNING id`,
+		entryID, cmd, time.Now().UTC(), actors, acceptedData)
+	if err != nil {
+		err = errors.Wrapf(err, "SaveEntryRevision() failed: entry_id=%d", entryID)
+	}
+	return
+}
+
+// Get ищет ревизию в БД по ее ID.
+func (r *EntryRevision) Get(ctx context.Context) error {
+	row, err := Get(
+		ctx,
+		`SELECT entry_id,path,json,status,last_modified,actors,accepted,cmd,created_at
+		FROM audio.album_entry_revision WHERE id=$1 LIMIT 1`,
+		r.ID)
+	if err != nil {
+		return errors.Wrapf(err, "EntryRevision.Get() select failed: id=%d", r.ID)
+	}
+	var actors, accepted []byte
+	err = row.Scan(&r.EntryID, &r.Path, &r.Json, &r.Status, &r.LastModified, &actors, &accepted,
+		&r.Cmd, &r.CreatedAt)
+	if err != nil {
+		return errors.Wrapf(err, "EntryRevision.Get() scan failed: id=%d", r.ID)
+	}
+	return r.UnmarshalRefs(actors, accepted)
+}
+
+// EntryRevisions возвращает ревизии Entry, начиная с последней.
+// Данные релиза (поле Json) и снимок связанных данных в выборку не включаются.
+func EntryRevisions(ctx context.Context, entryID int) ([]*EntryRevision, error) {
+	db, err := Conn(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "EntryRevisions() failed")
+	}
+
+	rows, err := db.Query(
+		ctx,
+		`SELECT id,entry_id,path,status,last_modified,cmd,created_at
+		FROM audio.album_entry_revision WHERE entry_id=$1 ORDER BY id DESC`,
+		entryID)
+	if err != nil {
+		return nil, errors.Wrap(err, "EntryRevisions() select failed")
+	}
+	defer rows.Close()
+
+	ret := []*EntryRevision{}
+	for rows.Next() {
+		var r EntryRevision
+		err = rows.Scan(&r.ID, &r.EntryID, &r.Path, &r.Status, &r.LastModified, &r.Cmd, &r.CreatedAt)
+		if err != nil {
+			return nil, errors.Wrap(err, "EntryRevisions() scan failed")
+		}
+		ret = append(ret, &r)
+	}
+
+	return ret, rows.Err()
+}
+
+// DeleteEntryRevisions удаляет все ревизии Entry.
+func DeleteEntryRevisions(ctx context.Context, entryID int) error {
+	err := Delete(ctx, "DELETE FROM audio.album_entry_revision WHERE entry_id=$1", entryID)
+	if err != nil {
+		err = errors.Wrap(err, "DeleteEntryRevisions() failed")
+	}
+	return err
+}
@@ -0,0 +1,183 @@
+package entity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Поля релиза, которыми может быть ограничен полнотекстовый поиск.
+// Жанры, лейблы и каталожные номера имеют в audio.release_tsv() общий вес D и
+// участвуют только в поиске без ограничения полей; для отбора по ним предназначены
+// условия точного совпадения EntrySearch.Genre, Label и Catno.
+const (
+	SearchFieldTitle = "title"
+	SearchFieldTrack = "track"
+	SearchFieldActor = "actor"
+)
+
+// Веса полей в полнотекстовом векторе audio.release_tsv().
+var searchFieldWeights = map[string]string{
+	SearchFieldTitle: "a",
+	SearchFieldTrack: "b",
+	SearchFieldActor: "c",
+}
+
+// Ошибки поиска.
+var (
+	ErrEmptySearch    = errors.New("empty search criteria")
+	ErrBadSearchField = errors.New("unknown search field")
+)
+
+// EntrySearch описывает условия поиска по метаданным релизов audio.album_entry.
+// Query задается в синтаксисе websearch_to_tsquery() и может быть ограничен полями Fields
+// (SearchFieldTitle, SearchFieldTrack, SearchFieldActor).
+// Genre, Label и Catno задают точное совпадение значений в JSON релиза.
+type EntrySearch struct {
+	Query  string   `json:"query,omitempty"`
+	Fields []string `json:"fields,omitempty"`
+	Genre  string   `json:"genre,omitempty"`
+	Label  string   `json:"label,omitempty"`
+	Catno  string   `json:"catno,omitempty"`
+	Limit  int      `json:"limit,omitempty"`
+	Offset int      `json:"offset,omitempty"`
+}
+
+// SearchResult описывает найденный каталог с оценкой релевантности и фрагментом
+// метаданных с подсвеченными совпадениями.
+type SearchResult struct {
+	Entry    *AlbumEntry `json:"entry"`
+	Rank     float32     `json:"rank"`
+	Headline string      `json:"headline,omitempty"`
+}
+
+// SearchEntries выполняет поиск каталогов по метаданным релизов.
+// Результаты упорядочены по убыванию релевантности.
+func SearchEntries(ctx context.Context, search *EntrySearch) ([]*SearchResult, error) {
+	db, err := Conn(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "SearchEntries() failed")
+	}
+
+	qry, args, err := search.query()
+	if err != nil {
+		return nil, errors.Wrap(err, "SearchEntries() failed")
+	}
+
+	rows, err := db.Query(ctx, qry, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "SearchEntries() select failed")
+	}
+	defer rows.Close()
+
+	ret := []*SearchResult{}
+	for rows.Next() {
+		res := SearchResult{Entry: &AlbumEntry{}}
+		err = rows.Scan(&res.Entry.ID, &res.Entry.Path, &res.Entry.Status,
+			&res.Entry.LastModified, &res.Rank, &res.Headline)
+		if err != nil {
+			return nil, errors.Wrap(err, "SearchEntries() scan failed")
+		}
+		ret = append(ret, &res)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "SearchEntries() select failed")
+	}
+
+	return ret, nil
+}
+
+// Формирует текст запроса и его параметры.
+// Полнотекстовое условие использует индекс idx_albumentry_tsv, условия точного совпадения -
+// индекс idx_albumentry_release (оператор @>).
+func (s *EntrySearch) query() (string, []interface{}, error) {
+	var where []string
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	rank, headline := "0::real", "''"
+	from := "audio.album_entry e"
+
+	if s.Query != "" {
+		from += ", websearch_to_tsquery('simple', " + arg(s.Query) + ") q"
+		where = append(where, "audio.release_tsv(e.json) @@ q")
+		rank = "ts_rank(audio.release_tsv(e.json), q)"
+		headline = "ts_headline('simple', audio.release_text(e.json), q, 'MaxFragments=3')"
+
+		if len(s.Fields) > 0 {
+			weights, err := s.weights()
+			if err != nil {
+				return "", nil, err
+			}
+			where = append(where,
+				"ts_filter(audio.release_tsv(e.json), "+arg(weights)+"::text::\"char\"[]) @@ q")
+		}
+	}
+
+	for _, contains := range s.containments() {
+		data, err := json.Marshal(contains)
+		if err != nil {
+			return "", nil, err
+		}
+		where = append(where, "e.json @> "+arg(string(data))+"::jsonb")
+	}
+
+	if len(where) == 0 {
+		return "", nil, ErrEmptySearch
+	}
+
+	qry := fmt.Sprintf(
+		"SELECT e.id,e.path,e.status,e.last_modified,%s,%s FROM %s WHERE %s"+
+			" ORDER BY 5 DESC,e.id LIMIT %s",
+		rank, headline, from, strings.Join(where, " AND "), arg(s.limit()))
+	if s.Offset > 0 {
+		qry += " OFFSET " + arg(s.Offset)
+	}
+
+	return qry, args, nil
+}
+
+func (s *EntrySearch) limit() int {
+	switch {
+	case s.Limit <= 0:
+		return DefaultListLimit
+	case s.Limit > MaxListLimit:
+		return MaxListLimit
+	}
+	return s.Limit
+}
+
+// Возвращает массив весов полнотекстового вектора для полей поиска в формате "{a,c}".
+func (s *EntrySearch) weights() (string, error) {
+	var ret []string
+	for _, fld := range s.Fields {
+		w, ok := searchFieldWeights[fld]
+		if !ok {
+			return "", errors.Wrap(ErrBadSearchField, fld)
+		}
+		ret = append(ret, w)
+	}
+	return "{" + strings.Join(ret, ",") + "}", nil
+}
+
+// Возвращает JSON-шаблоны для проверки вхождения в JSON релиза.
+func (s *EntrySearch) containments() (ret []interface{}) {
+	type obj = map[string]interface{}
+	type arr = []interface{}
+	if s.Genre != "" {
+		ret = append(ret, obj{"tracks": arr{obj{"record": obj{"genres": arr{s.Genre}}}}})
+	}
+	if s.Label != "" {
+		ret = append(ret, obj{"publishing": arr{obj{"name": s.Label}}})
+	}
+	if s.Catno != "" {
+		ret = append(ret, obj{"publishing": arr{obj{"catno": s.Catno}}})
+	}
+	return
+}
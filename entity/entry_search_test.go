@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntrySearchQuery(t *testing.T) {
+	s := &EntrySearch{
+		Query:  "after forever",
+		Fields: []string{SearchFieldTitle, SearchFieldActor},
+		Label:  "Transmission",
+		Limit:  MaxListLimit + 1,
+		Offset: 20,
+	}
+	qry, args, err := s.query()
+	require.NoError(t, err)
+	assert.Contains(t, qry, "websearch_to_tsquery('simple', $1) q")
+	assert.Contains(t, qry, "ts_filter(audio.release_tsv(e.json), $2::text::\"char\"[]) @@ q")
+	assert.Contains(t, qry, "e.json @> $3::jsonb")
+	assert.True(t, strings.HasSuffix(qry, "LIMIT $4 OFFSET $5"))
+	assert.Equal(t, []interface{}{
+		"after forever",
+		"{a,c}",
+		`{"publishing":[{"name":"Transmission"}]}`,
+		MaxListLimit,
+		20,
+	}, args)
+
+	// без полнотекстового запроса релевантность не оценивается
+	qry, args, err = (&EntrySearch{Genre: "Rock", Catno: "TM-022"}).query()
+	require.NoError(t, err)
+	assert.Contains(t, qry, "0::real,''")
+	assert.NotContains(t, qry, "release_tsv")
+	assert.Equal(t, []interface{}{
+		`{"tracks":[{"record":{"genres":["Rock"]}}]}`,
+		`{"publishing":[{"catno":"TM-022"}]}`,
+		DefaultListLimit,
+	}, args)
+
+	_, _, err = (&EntrySearch{}).query()
+	assert.ErrorIs(t, err, ErrEmptySearch)
+
+	// жанры, лейблы и каталожные номера не различимы по весу в полнотекстовом векторе
+	for _, fld := range []string{"genre", "label", "catno", "x"} {
+		_, _, err = (&EntrySearch{Query: "rock", Fields: []string{fld}}).query()
+		assert.ErrorIs(t, err, ErrBadSearchField, fld)
+	}
+}
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// LibraryEntries возвращает все записи audio.album_entry для сверки с файловой системой.
+// Данные релиза (поле Json) в выборку не включаются.
+func LibraryEntries(ctx context.Context) ([]*AlbumEntry, error) {
+	db, err := Conn(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "LibraryEntries() failed")
+	}
+
+	rows, err := db.Query(
+		ctx,
+		`SELECT id,path,status,last_modified,version,stale_since FROM audio.album_entry
+		ORDER BY path`)
+	if err != nil {
+		return nil, errors.Wrap(err, "LibraryEntries() select failed")
+	}
+	defer rows.Close()
+
+	ret := []*AlbumEntry{}
+	for rows.Next() {
+		var ent AlbumEntry
+		err = rows.Scan(
+			&ent.ID, &ent.Path, &ent.Status, &ent.LastModified, &ent.Version, &ent.StaleSince)
+		if err != nil {
+			return nil, errors.Wrap(err, "LibraryEntries() scan failed")
+		}
+		ret = append(ret, &ent)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "LibraryEntries() select failed")
+	}
+	return ret, nil
+}
+
+// MarkEntriesStale устанавливает время `since` обнаружения отсутствия каталогов записей
+// с указанными ID. Нулевое значение `since` снимает отметку.
+func MarkEntriesStale(ctx context.Context, ids []int, since time.Time) error {
+	tx, err := Tx(ctx)
+	if err != nil {
+		return errors.Wrap(err, "MarkEntriesStale() failed")
+	}
+	var arg *time.Time
+	if !since.IsZero() {
+		arg = &since
+	}
+	_, err = tx.Exec(
+		ctx, "UPDATE audio.album_entry SET stale_since=$2 WHERE id=ANY($1)", ids, arg)
+	if err != nil {
+		err = errors.Wrap(err, "MarkEntriesStale() failed")
+	}
+	return err
+}
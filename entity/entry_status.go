@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Значения типа audio.entry_status.
+const (
+	StatusWithoutMandatoryTags = "without_mandatory_tags"
+	StatusWithMandatoryTags    = "with_mandatory_tags"
+	StatusFinalyzed            = "finalyzed"
+)
+
+// ErrBadStatusTransition возвращается при недопустимом изменении статуса Entry.
+var ErrBadStatusTransition = errors.New("status transition is not allowed")
+
+// Допустимые переходы между статусами Entry.
+// Пустой исходный статус соответствует созданию записи.
+var statusTransitions = map[string][]string{
+	"":                         {StatusWithoutMandatoryTags, StatusWithMandatoryTags},
+	StatusWithoutMandatoryTags: {StatusWithMandatoryTags, StatusFinalyzed},
+	StatusWithMandatoryTags:    {StatusWithoutMandatoryTags, StatusFinalyzed},
+	StatusFinalyzed:            {StatusWithoutMandatoryTags, StatusWithMandatoryTags},
+}
+
+// StatusTransitions возвращает статусы, в которые может перейти Entry из статуса `status`.
+func StatusTransitions(status string) []string {
+	return statusTransitions[status]
+}
+
+// CheckStatusTransition проверяет допустимость изменения статуса Entry с `from` на `to`.
+// Сохранение текущего статуса допустимо всегда.
+func CheckStatusTransition(from, to string) error {
+	if from == to && from != "" {
+		return nil
+	}
+	for _, status := range statusTransitions[from] {
+		if status == to {
+			return nil
+		}
+	}
+	return errors.Wrapf(ErrBadStatusTransition, "%q -> %q", from, to)
+}
@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckStatusTransition(t *testing.T) {
+	assert.NoError(t, CheckStatusTransition("", StatusWithoutMandatoryTags))
+	assert.NoError(t, CheckStatusTransition(StatusWithoutMandatoryTags, StatusWithMandatoryTags))
+	assert.NoError(t, CheckStatusTransition(StatusWithMandatoryTags, StatusFinalyzed))
+	assert.NoError(t, CheckStatusTransition(StatusFinalyzed, StatusFinalyzed))
+	assert.NoError(t, CheckStatusTransition(StatusFinalyzed, StatusWithMandatoryTags))
+
+	assert.ErrorIs(t, CheckStatusTransition("", StatusFinalyzed), ErrBadStatusTransition)
+	assert.ErrorIs(t, CheckStatusTransition("", ""), ErrBadStatusTransition)
+	assert.ErrorIs(t, CheckStatusTransition(StatusWithMandatoryTags, "unknown"), ErrBadStatusTransition)
+}
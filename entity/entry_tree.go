@@ -0,0 +1,97 @@
+package entity
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// EntryMove описывает изменение пути Entry при перемещении дерева каталогов.
+type EntryMove struct {
+	EntryID int    `json:"entry_id"`
+	OldPath string `json:"old_path"`
+	NewPath string `json:"new_path"`
+}
+
+// Условие отбора записей дерева каталогов с корнем `prefix`.
+const treeCondition = "(path=$1 OR path LIKE $2)"
+
+// TreeEntries возвращает записи audio.album_entry дерева каталогов с корнем `prefix`,
+// упорядоченные по пути. В транзакции записи блокируются до ее завершения.
+// Данные релиза (поле Json) в выборку не включаются.
+func TreeEntries(ctx context.Context, prefix string) ([]*AlbumEntry, error) {
+	db, err := Conn(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "TreeEntries() failed")
+	}
+
+	rows, err := db.Query(
+		ctx,
+		`SELECT id,path,status,last_modified,version FROM audio.album_entry
+		WHERE `+treeCondition+` ORDER BY path FOR UPDATE`,
+		prefix, EscapeLike(prefix)+"/%")
+	if err != nil {
+		return nil, errors.Wrap(err, "TreeEntries() select failed")
+	}
+	defer rows.Close()
+
+	ret := []*AlbumEntry{}
+	for rows.Next() {
+		var ent AlbumEntry
+		err = rows.Scan(&ent.ID, &ent.Path, &ent.Status, &ent.LastModified, &ent.Version)
+		if err != nil {
+			return nil, errors.Wrap(err, "TreeEntries() scan failed")
+		}
+		ret = append(ret, &ent)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "TreeEntries() select failed")
+	}
+	return ret, nil
+}
+
+// ExistingPaths возвращает пути из списка `paths`, уже занятые записями audio.album_entry.
+func ExistingPaths(ctx context.Context, paths []string) ([]string, error) {
+	db, err := Conn(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "ExistingPaths() failed")
+	}
+
+	rows, err := db.Query(
+		ctx, "SELECT path FROM audio.album_entry WHERE path=ANY($1) ORDER BY path", paths)
+	if err != nil {
+		return nil, errors.Wrap(err, "ExistingPaths() select failed")
+	}
+	defer rows.Close()
+
+	var ret []string
+	for rows.Next() {
+		var path string
+		if err = rows.Scan(&path); err != nil {
+			return nil, errors.Wrap(err, "ExistingPaths() scan failed")
+		}
+		ret = append(ret, path)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "ExistingPaths() select failed")
+	}
+	return ret, nil
+}
+
+// MoveTree заменяет корень `oldPrefix` путей записей дерева каталогов на `newPrefix`
+// и увеличивает версии измененных записей. Возвращает число измененных записей.
+func MoveTree(ctx context.Context, oldPrefix, newPrefix string) (int64, error) {
+	tx, err := Tx(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "MoveTree() failed")
+	}
+	tag, err := tx.Exec(
+		ctx,
+		`UPDATE audio.album_entry SET path=$3||substr(path,length($1)+1),version=version+1
+		WHERE `+treeCondition,
+		oldPrefix, EscapeLike(oldPrefix)+"/%", newPrefix)
+	if err != nil {
+		return 0, errors.Wrapf(err, "MoveTree() failed: %q -> %q", oldPrefix, newPrefix)
+	}
+	return tag.RowsAffected(), nil
+}
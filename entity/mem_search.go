@@ -0,0 +1,319 @@
+package entity
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// Коэффициенты весов полнотекстового вектора, используемые ts_rank() по умолчанию.
+var searchWeightRanks = map[string]float32{"a": 1, "b": 0.4, "c": 0.2, "d": 0.1}
+
+// SearchMatcher описывает условия поиска, подготовленные для проверки релизов хранилищами,
+// не использующими PostgreSQL.
+// Запрос разбирается по правилам websearch_to_tsquery(): слова объединяются по AND,
+// "or" разделяет альтернативы, "-" перед словом исключает его, кавычки группируют слова.
+type SearchMatcher struct {
+	query   bool
+	clauses [][]searchTerm  // альтернативы, объединенные по OR
+	weights map[string]bool // веса полей поиска; nil - все поля
+	genre   string
+	label   string
+	catno   string
+}
+
+type searchTerm struct {
+	word   string
+	negate bool
+}
+
+// Термы и значения релиза, используемые при поиске.
+type releaseDoc struct {
+	terms  map[string]map[string]bool // терм -> веса полей, содержащих терм
+	genres map[string]bool
+	labels map[string]bool
+	catnos map[string]bool
+	text   string // аналог audio.release_text()
+}
+
+// Matcher подготавливает условия поиска для проверки релизов без PostgreSQL.
+func (s *EntrySearch) Matcher() (*SearchMatcher, error) {
+	ret := &SearchMatcher{query: s.Query != "", genre: s.Genre, label: s.Label, catno: s.Catno}
+	if !ret.query && s.Genre == "" && s.Label == "" && s.Catno == "" {
+		return nil, ErrEmptySearch
+	}
+	if !ret.query {
+		return ret, nil
+	}
+	if len(s.Fields) > 0 {
+		ret.weights = map[string]bool{}
+		for _, fld := range s.Fields {
+			w, ok := searchFieldWeights[fld]
+			if !ok {
+				return nil, errors.Wrap(ErrBadSearchField, fld)
+			}
+			ret.weights[w] = true
+		}
+	}
+	clause := []searchTerm{}
+	for _, token := range searchTokens(s.Query) {
+		if strings.EqualFold(token, "or") {
+			ret.clauses = append(ret.clauses, clause)
+			clause = []searchTerm{}
+			continue
+		}
+		negate := strings.HasPrefix(token, "-")
+		for _, word := range searchWords(token) {
+			clause = append(clause, searchTerm{word: word, negate: negate})
+		}
+	}
+	ret.clauses = append(ret.clauses, clause)
+	return ret, nil
+}
+
+// Page упорядочивает результаты поиска по убыванию релевантности и возвращает страницу
+// результатов.
+func (s *EntrySearch) Page(ret []*SearchResult) []*SearchResult {
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Rank != ret[j].Rank {
+			return ret[i].Rank > ret[j].Rank
+		}
+		return ret[i].Entry.ID < ret[j].Entry.ID
+	})
+	if s.Offset >= len(ret) {
+		return []*SearchResult{}
+	}
+	ret = ret[s.Offset:]
+	if limit := s.limit(); len(ret) > limit {
+		ret = ret[:limit]
+	}
+	return ret
+}
+
+// Разбивает запрос на лексемы с учетом кавычек.
+func searchTokens(query string) (ret []string) {
+	var token strings.Builder
+	quoted := false
+	flush := func() {
+		if token.Len() > 0 {
+			ret = append(ret, token.String())
+			token.Reset()
+		}
+	}
+	for _, r := range query {
+		switch {
+		case r == '"':
+			flush()
+			quoted = !quoted
+		case unicode.IsSpace(r) && !quoted:
+			flush()
+		default:
+			token.WriteRune(r)
+		}
+	}
+	flush()
+	return
+}
+
+// Разбивает текст на слова в нижнем регистре аналогично парсеру конфигурации 'simple'.
+func searchWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Match проверяет релиз Entry на соответствие условиям поиска и возвращает результат
+// поиска или nil.
+func (q *SearchMatcher) Match(ent *AlbumEntry) *SearchResult {
+	doc, ok := newReleaseDoc(ent.Json)
+	if !ok {
+		return nil
+	}
+	if q.genre != "" && !doc.genres[q.genre] ||
+		q.label != "" && !doc.labels[q.label] ||
+		q.catno != "" && !doc.catnos[q.catno] {
+		return nil
+	}
+	res := &SearchResult{Entry: entryHeader(*ent)}
+	res.Entry.StaleSince = nil
+	if !q.query {
+		return res
+	}
+
+	matched := false
+	words := map[string]bool{}
+	for _, clause := range q.clauses {
+		if q.matchClause(doc, clause) {
+			matched = true
+			for _, term := range clause {
+				if !term.negate {
+					words[term.word] = true
+				}
+			}
+		}
+	}
+	if !matched {
+		return nil
+	}
+	for word := range words {
+		var rank float32
+		for w := range doc.terms[word] {
+			if q.allowed(w) && searchWeightRanks[w] > rank {
+				rank = searchWeightRanks[w]
+			}
+		}
+		res.Rank += rank
+	}
+	res.Headline = highlight(doc.text, words)
+	return res
+}
+
+func (q *SearchMatcher) allowed(weight string) bool {
+	return q.weights == nil || q.weights[weight]
+}
+
+// Проверяет наличие всех слов альтернативы и отсутствие исключенных слов.
+func (q *SearchMatcher) matchClause(doc *releaseDoc, clause []searchTerm) bool {
+	found := false
+	for _, term := range clause {
+		has := false
+		for w := range doc.terms[term.word] {
+			has = has || q.allowed(w)
+		}
+		if has == term.negate {
+			return false
+		}
+		found = found || !term.negate
+	}
+	return found
+}
+
+// Выделяет в тексте слова `words` аналогично ts_headline().
+func highlight(text string, words map[string]bool) string {
+	var ret, word strings.Builder
+	flush := func() {
+		if word.Len() == 0 {
+			return
+		}
+		if words[strings.ToLower(word.String())] {
+			ret.WriteString("<b>" + word.String() + "</b>")
+		} else {
+			ret.WriteString(word.String())
+		}
+		word.Reset()
+	}
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			word.WriteRune(r)
+			continue
+		}
+		flush()
+		ret.WriteRune(r)
+	}
+	flush()
+	return ret.String()
+}
+
+// Извлекает из JSON релиза данные для поиска аналогично audio.release_tsv().
+func newReleaseDoc(data []byte) (*releaseDoc, bool) {
+	var release map[string]interface{}
+	if len(data) == 0 || json.Unmarshal(data, &release) != nil {
+		return nil, false
+	}
+	doc := &releaseDoc{
+		terms:  map[string]map[string]bool{},
+		genres: map[string]bool{},
+		labels: map[string]bool{},
+		catnos: map[string]bool{},
+	}
+	add := func(weight, s string) {
+		for _, word := range searchWords(s) {
+			if doc.terms[word] == nil {
+				doc.terms[word] = map[string]bool{}
+			}
+			doc.terms[word][weight] = true
+		}
+	}
+	addKeys := func(weight string, v interface{}, names map[string]bool) {
+		obj, _ := v.(map[string]interface{})
+		for name := range obj {
+			add(weight, name)
+			if names != nil {
+				names[name] = true
+			}
+		}
+	}
+
+	title, _ := release["title"].(string)
+	add("a", title)
+	addKeys("c", release["actors"], nil)
+	addKeys("c", release["actors_roles"], nil)
+
+	var trackTitles []string
+	trackActors := map[string]bool{}
+	tracks, _ := release["tracks"].([]interface{})
+	for _, v := range tracks {
+		track, _ := v.(map[string]interface{})
+		if s, ok := track["title"].(string); ok {
+			add("b", s)
+			trackTitles = append(trackTitles, s)
+		}
+		addKeys("c", track["actors"], trackActors)
+		addKeys("c", track["actor_roles"], trackActors)
+		record, _ := track["record"].(map[string]interface{})
+		addKeys("c", record["actors"], trackActors)
+		addKeys("c", record["actor_roles"], trackActors)
+		composition, _ := track["composition"].(map[string]interface{})
+		addKeys("c", composition["actors"], nil)
+		addKeys("c", composition["actor_roles"], nil)
+		genres, _ := record["genres"].([]interface{})
+		for _, g := range genres {
+			if s, ok := g.(string); ok {
+				add("d", s)
+				doc.genres[s] = true
+			}
+		}
+	}
+
+	var publishing []string
+	pubs, _ := release["publishing"].([]interface{})
+	for _, v := range pubs {
+		pub, _ := v.(map[string]interface{})
+		name, _ := pub["name"].(string)
+		catno, _ := pub["catno"].(string)
+		add("d", name)
+		add("d", catno)
+		doc.labels[name] = name != ""
+		doc.catnos[catno] = catno != ""
+		publishing = append(publishing, strings.TrimSpace(name+" "+catno))
+	}
+
+	var parts []string
+	for _, part := range []string{
+		title,
+		strings.Join(trackTitles, ", "),
+		strings.Join(sortedKeys(trackActors), ", "),
+		strings.Join(sortedKeys(doc.genres), ", "),
+		strings.Join(publishing, ", "),
+	} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	doc.text = strings.Join(parts, " | ")
+	return doc, true
+}
+
+func sortedKeys(m map[string]bool) []string {
+	ret := make([]string, 0, len(m))
+	for k, ok := range m {
+		if ok {
+			ret = append(ret, k)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
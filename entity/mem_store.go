@@ -0,0 +1,989 @@
+package entity
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Ошибки работы с хранилищем в памяти.
+var (
+	ErrTxRequired = errors.New("operation requires a transaction")
+	ErrTxDone     = errors.New("transaction has already been committed or rolled back")
+)
+
+// Значения перечислений схемы БД.
+var (
+	entryStatuses = enumValues(StatusWithoutMandatoryTags, StatusWithMandatoryTags, StatusFinalyzed)
+	extDBs        = enumValues("rutracker", "discogs", "musicbrainz")
+	entityTypes   = enumValues("actor", "work", "composition", "record", "album_entry",
+		"suggestion", "track", "label", "disc")
+	pictTypes = enumValues("png_icon", "other_icon", "cover_front", "cover_back", "leaflet",
+		"media", "lad_artist", "artist", "conductor", "orchestra", "composer", "lyricist",
+		"recording_location", "during_recording", "during_performance", "movie_screen",
+		"bright_color_fish", "illustration", "artist_logotype", "publisher_logotype")
+)
+
+// MemStore реализует хранилище Store в памяти процесса.
+// Хранилище соблюдает ограничения схемы БД: уникальность путей каталогов и первичных
+// ключей, ссылочную целостность данных каталогов. Транзакции выполняются
+// последовательно: Begin() ожидает завершения текущей транзакции, изменения
+// транзакции выполняются над копией данных и становятся видимыми после Commit().
+// Значения перечислений (статус каталога, внешняя БД, тип изображения и сущности)
+// проверяются так же, как в схеме БД: недопустимое значение приводит к ошибке ErrBadValue.
+//
+// Хранилище предназначено для тестов и небольших наборов данных: Begin() копирует все
+// таблицы хранилища, поэтому время начала транзакции пропорционально объему данных,
+// а транзакции, в том числе не изменяющие данных, не выполняются одновременно.
+type MemStore struct {
+	txSem chan struct{} // транзакция, удерживается от Begin() до Commit()/Rollback()
+	mu    sync.RWMutex  // зафиксированные данные
+	data  *memData
+}
+
+var _ Store = (*MemStore)(nil)
+
+// Ключи записей таблиц хранилища в памяти.
+type (
+	actorKey struct {
+		entryID int
+		name    string
+	}
+	pictureKey struct {
+		entType  string
+		entID    int
+		pictType string
+	}
+	suggestionKey struct {
+		entryID int
+		extDB   string
+		extID   string
+	}
+)
+
+// Таблицы хранилища в памяти. Записи хранятся по значению, срезы байт в записях
+// не изменяются после записи, что позволяет копировать таблицы поверхностно.
+type memData struct {
+	entries        map[int]AlbumEntry
+	paths          map[string]int
+	actors         map[actorKey]Actor
+	pictures       map[pictureKey]Picture
+	suggestions    map[suggestionKey]Suggestion
+	badSuggestions map[suggestionKey]BadSuggestion
+	accepted       map[int]AcceptedSuggestion
+	revisions      map[int]EntryRevision
+	events         []memEvent
+	lastEntryID    int
+	lastRevisionID int
+	lastEventID    int
+}
+
+type memEvent struct {
+	ev        EntryEvent
+	published bool
+}
+
+// Транзакция хранилища в памяти.
+type memTx struct {
+	s    *MemStore
+	data *memData
+	done bool
+}
+
+type memTxKey struct{}
+
+// NewMemStore создает пустое хранилище в памяти.
+func NewMemStore() *MemStore {
+	return &MemStore{txSem: make(chan struct{}, 1), data: newMemData()}
+}
+
+func newMemData() *memData {
+	return &memData{
+		entries:        map[int]AlbumEntry{},
+		paths:          map[string]int{},
+		actors:         map[actorKey]Actor{},
+		pictures:       map[pictureKey]Picture{},
+		suggestions:    map[suggestionKey]Suggestion{},
+		badSuggestions: map[suggestionKey]BadSuggestion{},
+		accepted:       map[int]AcceptedSuggestion{},
+		revisions:      map[int]EntryRevision{},
+	}
+}
+
+func (d *memData) clone() *memData {
+	ret := *d
+	ret.entries = make(map[int]AlbumEntry, len(d.entries))
+	for k, v := range d.entries {
+		ret.entries[k] = v
+	}
+	ret.paths = make(map[string]int, len(d.paths))
+	for k, v := range d.paths {
+		ret.paths[k] = v
+	}
+	ret.actors = make(map[actorKey]Actor, len(d.actors))
+	for k, v := range d.actors {
+		ret.actors[k] = v
+	}
+	ret.pictures = make(map[pictureKey]Picture, len(d.pictures))
+	for k, v := range d.pictures {
+		ret.pictures[k] = v
+	}
+	ret.suggestions = make(map[suggestionKey]Suggestion, len(d.suggestions))
+	for k, v := range d.suggestions {
+		ret.suggestions[k] = v
+	}
+	ret.badSuggestions = make(map[suggestionKey]BadSuggestion, len(d.badSuggestions))
+	for k, v := range d.badSuggestions {
+		ret.badSuggestions[k] = v
+	}
+	ret.accepted = make(map[int]AcceptedSuggestion, len(d.accepted))
+	for k, v := range d.accepted {
+		ret.accepted[k] = v
+	}
+	ret.revisions = make(map[int]EntryRevision, len(d.revisions))
+	for k, v := range d.revisions {
+		ret.revisions[k] = v
+	}
+	ret.events = append([]memEvent(nil), d.events...)
+	return &ret
+}
+
+// Проверяет наличие записи каталога, на которую ссылается запись другой таблицы.
+func (d *memData) checkEntryRef(entryID int) error {
+	if _, ok := d.entries[entryID]; !ok {
+		return errors.Wrapf(ErrForeignKey, "entry_id=%d", entryID)
+	}
+	return nil
+}
+
+// Проверяет отсутствие записей других таблиц, ссылающихся на запись каталога.
+func (d *memData) checkEntryRefs(entryID int) error {
+	referenced := false
+	for k := range d.actors {
+		referenced = referenced || k.entryID == entryID
+	}
+	for k := range d.suggestions {
+		referenced = referenced || k.entryID == entryID
+	}
+	for k := range d.badSuggestions {
+		referenced = referenced || k.entryID == entryID
+	}
+	for _, rev := range d.revisions {
+		referenced = referenced || rev.EntryID == entryID
+	}
+	_, accepted := d.accepted[entryID]
+	if referenced || accepted {
+		return errors.Wrapf(ErrForeignKey, "entry is still referenced: entry_id=%d", entryID)
+	}
+	return nil
+}
+
+// Возвращает множество допустимых значений перечисления.
+func enumValues(values ...string) map[string]bool {
+	ret := make(map[string]bool, len(values))
+	for _, v := range values {
+		ret[v] = true
+	}
+	return ret
+}
+
+// Проверяет, что значение `value` поля `field` входит в перечисление `values`.
+func checkEnum(values map[string]bool, field, value string) error {
+	if !values[value] {
+		return errors.Wrapf(ErrBadValue, "%s=%q", field, value)
+	}
+	return nil
+}
+
+// Записывает каталог с проверкой статуса и уникальности пути.
+func (d *memData) putEntry(ent AlbumEntry) error {
+	if err := checkEnum(entryStatuses, "status", ent.Status); err != nil {
+		return err
+	}
+	if id, ok := d.paths[ent.Path]; ok && id != ent.ID {
+		return errors.Wrapf(ErrDuplicatePath, "path=%s", ent.Path)
+	}
+	if old, ok := d.entries[ent.ID]; ok && old.Path != ent.Path {
+		delete(d.paths, old.Path)
+	}
+	d.entries[ent.ID] = ent
+	d.paths[ent.Path] = ent.ID
+	return nil
+}
+
+// Возвращает транзакцию из контекста.
+func memTxFrom(ctx context.Context) (*memTx, bool) {
+	tx, ok := ctx.Value(memTxKey{}).(*memTx)
+	return tx, ok && !tx.done
+}
+
+// Выполняет чтение данных в транзакции контекста или в зафиксированных данных.
+func (s *MemStore) read(ctx context.Context, fn func(d *memData) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if tx, ok := memTxFrom(ctx); ok && tx.s == s {
+		return fn(tx.data)
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return fn(s.data)
+}
+
+// Выполняет изменение данных в транзакции контекста.
+func (s *MemStore) write(ctx context.Context, fn func(d *memData) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	tx, ok := memTxFrom(ctx)
+	if !ok || tx.s != s {
+		return ErrTxRequired
+	}
+	return fn(tx.data)
+}
+
+// Begin начинает транзакцию над копией всех данных хранилища. Если открыта другая
+// транзакция, метод ожидает ее завершения или завершения контекста `ctx`.
+func (s *MemStore) Begin(ctx context.Context) (context.Context, StoreTx, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	select {
+	case s.txSem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+	s.mu.RLock()
+	tx := &memTx{s: s, data: s.data.clone()}
+	s.mu.RUnlock()
+	return context.WithValue(ctx, memTxKey{}, tx), tx, nil
+}
+
+// Commit фиксирует изменения транзакции.
+func (tx *memTx) Commit(ctx context.Context) error {
+	if tx.done {
+		return ErrTxDone
+	}
+	tx.done = true
+	tx.s.mu.Lock()
+	tx.s.data = tx.data
+	tx.s.mu.Unlock()
+	<-tx.s.txSem
+	return nil
+}
+
+// Rollback отменяет изменения транзакции.
+func (tx *memTx) Rollback(ctx context.Context) error {
+	if tx.done {
+		return ErrTxDone
+	}
+	tx.done = true
+	<-tx.s.txSem
+	return nil
+}
+
+func cloneBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	return append([]byte{}, b...)
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	ret := *t
+	return &ret
+}
+
+// Возвращает копию записи каталога без данных релиза.
+func entryHeader(ent AlbumEntry) *AlbumEntry {
+	ent.Json = nil
+	ent.StaleSince = cloneTime(ent.StaleSince)
+	return &ent
+}
+
+func (s *MemStore) GetEntry(ctx context.Context, ent *AlbumEntry) error {
+	return s.read(ctx, func(d *memData) error {
+		id := ent.ID
+		if id == 0 {
+			id = d.paths[ent.Path]
+		}
+		found, ok := d.entries[id]
+		if !ok {
+			return errors.Wrapf(ErrNotFound, "GetEntry() failed: id=%d", ent.ID)
+		}
+		*ent = found
+		ent.Json = cloneBytes(found.Json)
+		ent.StaleSince = cloneTime(found.StaleSince)
+		return nil
+	})
+}
+
+func (s *MemStore) CreateEntry(ctx context.Context, ent *AlbumEntry) error {
+	return s.write(ctx, func(d *memData) error {
+		if _, ok := d.paths[ent.Path]; ok {
+			return errors.Wrapf(ErrDuplicatePath, "CreateEntry() failed: path=%s", ent.Path)
+		}
+		if err := checkEnum(entryStatuses, "status", ent.Status); err != nil {
+			return errors.Wrap(err, "CreateEntry() failed")
+		}
+		d.lastEntryID++
+		stored := AlbumEntry{
+			ID:           d.lastEntryID,
+			Path:         ent.Path,
+			Json:         cloneBytes(ent.Json),
+			Status:       ent.Status,
+			LastModified: ent.LastModified,
+			Version:      1}
+		if err := d.putEntry(stored); err != nil {
+			return err
+		}
+		ent.ID, ent.Version = stored.ID, stored.Version
+		return nil
+	})
+}
+
+func (s *MemStore) UpdateEntry(ctx context.Context, ent *AlbumEntry) error {
+	return s.write(ctx, func(d *memData) error {
+		stored, ok := d.entries[ent.ID]
+		if !ok {
+			return errors.Wrapf(ErrNotFound, "UpdateEntry() failed: id=%d", ent.ID)
+		}
+		if ent.Version != 0 && ent.Version != stored.Version {
+			return errors.Wrapf(ErrVersionConflict,
+				"UpdateEntry() failed: id=%d, version=%d, current version=%d",
+				ent.ID, ent.Version, stored.Version)
+		}
+		stored.Path = ent.Path
+		stored.Json = cloneBytes(ent.Json)
+		stored.Status = ent.Status
+		stored.LastModified = ent.LastModified
+		stored.Version++
+		if err := d.putEntry(stored); err != nil {
+			return errors.Wrapf(err, "UpdateEntry() failed: id=%d", ent.ID)
+		}
+		ent.Version = stored.Version
+		return nil
+	})
+}
+
+func (s *MemStore) DeleteEntry(ctx context.Context, ent *AlbumEntry) error {
+	return s.write(ctx, func(d *memData) error {
+		id := ent.ID
+		if id == 0 {
+			id = d.paths[ent.Path]
+		}
+		stored, ok := d.entries[id]
+		if !ok {
+			return nil
+		}
+		if err := d.checkEntryRefs(id); err != nil {
+			return errors.Wrap(err, "DeleteEntry() failed")
+		}
+		delete(d.entries, id)
+		delete(d.paths, stored.Path)
+		return nil
+	})
+}
+
+func (s *MemStore) ListEntries(
+	ctx context.Context, filter *EntryFilter) (ret []*AlbumEntry, next string, err error) {
+
+	c, err := filter.Position()
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ListEntries() failed")
+	}
+	err = s.read(ctx, func(d *memData) error {
+		ret = []*AlbumEntry{}
+		for _, ent := range d.entries {
+			if filter.match(d, &ent) && c.before(filter, &ent) {
+				ret = append(ret, entryHeader(ent))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		if filter.Desc {
+			return entryLess(filter.SortField(), ret[j], ret[i])
+		}
+		return entryLess(filter.SortField(), ret[i], ret[j])
+	})
+	if limit := filter.PageLimit(); len(ret) > limit {
+		ret = ret[:limit]
+		next = filter.NextCursor(ret[limit-1])
+	}
+	return ret, next, nil
+}
+
+// Проверяет соответствие записи условиям отбора фильтра.
+func (f *EntryFilter) match(d *memData, ent *AlbumEntry) bool {
+	switch {
+	case f.Status != "" && ent.Status != f.Status,
+		!strings.HasPrefix(ent.Path, f.PathPrefix),
+		f.ModifiedAfter != nil && ent.LastModified.Before(*f.ModifiedAfter),
+		f.ModifiedBefore != nil && !ent.LastModified.Before(*f.ModifiedBefore):
+		return false
+	}
+	if f.HasSuggestions != nil {
+		has := false
+		for k := range d.suggestions {
+			has = has || k.entryID == ent.ID
+		}
+		return has == *f.HasSuggestions
+	}
+	return true
+}
+
+// Проверяет, что запись находится после позиции курсора с учетом направления сортировки.
+// Для первой страницы (nil) возвращает true.
+func (c *EntryCursor) before(f *EntryFilter, ent *AlbumEntry) bool {
+	if c == nil {
+		return true
+	}
+	pos := &AlbumEntry{ID: c.ID, Path: c.Value, LastModified: c.Modified}
+	if f.Desc {
+		return entryLess(c.SortBy, ent, pos)
+	}
+	return entryLess(c.SortBy, pos, ent)
+}
+
+// Сравнивает записи по полю сортировки и ID.
+func entryLess(sortBy string, a, b *AlbumEntry) bool {
+	switch sortBy {
+	case SortByPath:
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+	case SortByLastModified:
+		if !a.LastModified.Equal(b.LastModified) {
+			return a.LastModified.Before(b.LastModified)
+		}
+	}
+	return a.ID < b.ID
+}
+
+func (s *MemStore) SearchEntries(
+	ctx context.Context, search *EntrySearch) (ret []*SearchResult, err error) {
+
+	q, err := search.Matcher()
+	if err != nil {
+		return nil, errors.Wrap(err, "SearchEntries() failed")
+	}
+	err = s.read(ctx, func(d *memData) error {
+		ret = []*SearchResult{}
+		for _, ent := range d.entries {
+			if res := q.Match(&ent); res != nil {
+				ret = append(ret, res)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return search.Page(ret), nil
+}
+
+// Возвращает записи дерева каталогов с корнем `prefix`, упорядоченные по пути.
+func treeEntries(d *memData, prefix string) []*AlbumEntry {
+	ret := []*AlbumEntry{}
+	for _, ent := range d.entries {
+		if prefix == "" || ent.Path == prefix || strings.HasPrefix(ent.Path, prefix+"/") {
+			ret = append(ret, entryHeader(ent))
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Path < ret[j].Path })
+	return ret
+}
+
+func (s *MemStore) TreeEntries(ctx context.Context, prefix string) (ret []*AlbumEntry, err error) {
+	err = s.read(ctx, func(d *memData) error {
+		ret = treeEntries(d, prefix)
+		for _, ent := range ret {
+			ent.StaleSince = nil
+		}
+		return nil
+	})
+	return
+}
+
+func (s *MemStore) ExistingPaths(ctx context.Context, paths []string) (ret []string, err error) {
+	err = s.read(ctx, func(d *memData) error {
+		for _, path := range paths {
+			if _, ok := d.paths[path]; ok {
+				ret = append(ret, path)
+			}
+		}
+		return nil
+	})
+	sort.Strings(ret)
+	return
+}
+
+func (s *MemStore) MoveTree(ctx context.Context, oldPrefix, newPrefix string) (n int64, err error) {
+	err = s.write(ctx, func(d *memData) error {
+		for _, ent := range treeEntries(d, oldPrefix) {
+			stored := d.entries[ent.ID]
+			stored.Path = newPrefix + strings.TrimPrefix(stored.Path, oldPrefix)
+			stored.Version++
+			if err := d.putEntry(stored); err != nil {
+				return errors.Wrapf(err, "MoveTree() failed: %q -> %q", oldPrefix, newPrefix)
+			}
+			n++
+		}
+		return nil
+	})
+	return
+}
+
+func (s *MemStore) LibraryEntries(ctx context.Context) (ret []*AlbumEntry, err error) {
+	err = s.read(ctx, func(d *memData) error {
+		ret = treeEntries(d, "")
+		return nil
+	})
+	return
+}
+
+func (s *MemStore) MarkEntriesStale(ctx context.Context, ids []int, since time.Time) error {
+	return s.write(ctx, func(d *memData) error {
+		for _, id := range ids {
+			ent, ok := d.entries[id]
+			if !ok {
+				continue
+			}
+			ent.StaleSince = nil
+			if !since.IsZero() {
+				ent.StaleSince = cloneTime(&since)
+			}
+			d.entries[id] = ent
+		}
+		return nil
+	})
+}
+
+// Возвращает копии акторов каталога, упорядоченные по имени.
+func (d *memData) entryActors(entryID int) (ret []*Actor) {
+	for k, actor := range d.actors {
+		if k.entryID == entryID {
+			actor := actor
+			actor.IDs = append([][2]string(nil), actor.IDs...)
+			ret = append(ret, &actor)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return
+}
+
+// Заменяет снимок акторов и принятого предложения ревизии его копией.
+func cloneRevisionRefs(rev *EntryRevision) {
+	if rev.Actors != nil {
+		actors := make([]*Actor, 0, len(rev.Actors))
+		for _, actor := range rev.Actors {
+			actor := *actor
+			actor.IDs = append([][2]string(nil), actor.IDs...)
+			actors = append(actors, &actor)
+		}
+		rev.Actors = actors
+	}
+	if rev.Accepted != nil {
+		accepted := *rev.Accepted
+		rev.Accepted = &accepted
+	}
+}
+
+func (s *MemStore) EntryActors(ctx context.Context, entryID int) (ret []*Actor, err error) {
+	err = s.read(ctx, func(d *memData) error {
+		ret = d.entryActors(entryID)
+		return nil
+	})
+	return
+}
+
+func (s *MemStore) CreateActor(ctx context.Context, actor *Actor) error {
+	return s.write(ctx, func(d *memData) error {
+		if err := d.checkEntryRef(actor.EntryID); err != nil {
+			return errors.Wrap(err, "CreateActor() failed")
+		}
+		key := actorKey{actor.EntryID, actor.Name}
+		if _, ok := d.actors[key]; ok {
+			return errors.Wrapf(ErrDuplicateKey,
+				"CreateActor() failed: entry_id=%d, name=%s", actor.EntryID, actor.Name)
+		}
+		stored := *actor
+		stored.IDs = append([][2]string(nil), actor.IDs...)
+		d.actors[key] = stored
+		return nil
+	})
+}
+
+func (s *MemStore) UpdateActor(ctx context.Context, actor *Actor) error {
+	return s.write(ctx, func(d *memData) error {
+		key := actorKey{actor.EntryID, actor.Name}
+		stored, ok := d.actors[key]
+		if !ok {
+			return nil
+		}
+		stored.IDs = append([][2]string(nil), actor.IDs...)
+		stored.EntityMask = actor.EntityMask
+		d.actors[key] = stored
+		return nil
+	})
+}
+
+func (s *MemStore) DeleteActor(ctx context.Context, actor *Actor) error {
+	return s.write(ctx, func(d *memData) error {
+		delete(d.actors, actorKey{actor.EntryID, actor.Name})
+		return nil
+	})
+}
+
+func (s *MemStore) DeleteEntryActors(ctx context.Context, entryID int) error {
+	return s.write(ctx, func(d *memData) error {
+		for k := range d.actors {
+			if k.entryID == entryID {
+				delete(d.actors, k)
+			}
+		}
+		return nil
+	})
+}
+
+func (s *MemStore) EntryPictures(ctx context.Context, entryID int) (ret []*Picture, err error) {
+	err = s.read(ctx, func(d *memData) error {
+		ret = []*Picture{}
+		for k, pict := range d.pictures {
+			if k.entType == "album_entry" && k.entID == entryID {
+				pict := pict
+				pict.Data = cloneBytes(pict.Data)
+				ret = append(ret, &pict)
+			}
+		}
+		return nil
+	})
+	sort.Slice(ret, func(i, j int) bool { return ret[i].PictType < ret[j].PictType })
+	return
+}
+
+func (s *MemStore) CreatePicture(ctx context.Context, pict *Picture) error {
+	return s.write(ctx, func(d *memData) error {
+		if err := checkEnum(entityTypes, "entity_type", pict.EntType); err != nil {
+			return errors.Wrap(err, "CreatePicture() failed")
+		}
+		if err := checkEnum(pictTypes, "pict_type", pict.PictType); err != nil {
+			return errors.Wrap(err, "CreatePicture() failed")
+		}
+		key := pictureKey{pict.EntType, pict.EntID, pict.PictType}
+		if _, ok := d.pictures[key]; ok {
+			return errors.Wrapf(ErrDuplicateKey,
+				"CreatePicture() failed: entity_type=%s, entity_id=%d, pict_type=%s",
+				pict.EntType, pict.EntID, pict.PictType)
+		}
+		stored := *pict
+		stored.Data = cloneBytes(pict.Data)
+		d.pictures[key] = stored
+		return nil
+	})
+}
+
+func (s *MemStore) DeletePicture(ctx context.Context, pict *Picture) error {
+	return s.write(ctx, func(d *memData) error {
+		delete(d.pictures, pictureKey{pict.EntType, pict.EntID, pict.PictType})
+		return nil
+	})
+}
+
+func (s *MemStore) DeleteEntryPictures(ctx context.Context, entryID int) error {
+	return s.write(ctx, func(d *memData) error {
+		for k := range d.pictures {
+			if k.entType == "album_entry" && k.entID == entryID {
+				delete(d.pictures, k)
+			}
+		}
+		return nil
+	})
+}
+
+func (s *MemStore) EntrySuggestions(
+	ctx context.Context, entryID int) (ret []*Suggestion, err error) {
+
+	err = s.read(ctx, func(d *memData) error {
+		ret = []*Suggestion{}
+		for k, suggestion := range d.suggestions {
+			if k.entryID == entryID {
+				suggestion := suggestion
+				suggestion.Json = cloneBytes(suggestion.Json)
+				ret = append(ret, &suggestion)
+			}
+		}
+		return nil
+	})
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].ExtDB != ret[j].ExtDB {
+			return ret[i].ExtDB < ret[j].ExtDB
+		}
+		return ret[i].ExtID < ret[j].ExtID
+	})
+	return
+}
+
+func (s *MemStore) GetSuggestion(ctx context.Context, suggestion *Suggestion) error {
+	return s.read(ctx, func(d *memData) error {
+		stored, ok := d.suggestions[suggestionKey{suggestion.EntryID, suggestion.ExtDB, suggestion.ExtID}]
+		if !ok {
+			return errors.Wrapf(ErrNotFound,
+				"GetSuggestion() failed: entry_id=%d, ext_db=%s, ext_id=%s",
+				suggestion.EntryID, suggestion.ExtDB, suggestion.ExtID)
+		}
+		suggestion.Json = cloneBytes(stored.Json)
+		suggestion.Score = stored.Score
+		return nil
+	})
+}
+
+func (s *MemStore) CreateSuggestion(ctx context.Context, suggestion *Suggestion) error {
+	return s.write(ctx, func(d *memData) error {
+		if err := d.checkEntryRef(suggestion.EntryID); err != nil {
+			return errors.Wrap(err, "CreateSuggestion() failed")
+		}
+		if err := checkEnum(extDBs, "ext_db", suggestion.ExtDB); err != nil {
+			return errors.Wrap(err, "CreateSuggestion() failed")
+		}
+		key := suggestionKey{suggestion.EntryID, suggestion.ExtDB, suggestion.ExtID}
+		if _, ok := d.suggestions[key]; ok {
+			return errors.Wrapf(ErrDuplicateKey,
+				"CreateSuggestion() failed: entry_id=%d, ext_db=%s, ext_id=%s",
+				suggestion.EntryID, suggestion.ExtDB, suggestion.ExtID)
+		}
+		stored := *suggestion
+		stored.Json = cloneBytes(suggestion.Json)
+		d.suggestions[key] = stored
+		return nil
+	})
+}
+
+func (s *MemStore) DeleteSuggestion(ctx context.Context, suggestion *Suggestion) error {
+	return s.write(ctx, func(d *memData) error {
+		delete(d.suggestions, suggestionKey{suggestion.EntryID, suggestion.ExtDB, suggestion.ExtID})
+		return nil
+	})
+}
+
+func (s *MemStore) DeleteEntrySuggestions(ctx context.Context, entryID int) error {
+	return s.write(ctx, func(d *memData) error {
+		for k := range d.suggestions {
+			if k.entryID == entryID {
+				delete(d.suggestions, k)
+			}
+		}
+		return nil
+	})
+}
+
+func (s *MemStore) EntryBadSuggestions(
+	ctx context.Context, entryID int) (ret []*BadSuggestion, err error) {
+
+	err = s.read(ctx, func(d *memData) error {
+		ret = []*BadSuggestion{}
+		for k, bad := range d.badSuggestions {
+			if k.entryID == entryID {
+				bad := bad
+				ret = append(ret, &bad)
+			}
+		}
+		return nil
+	})
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].ExtDB != ret[j].ExtDB {
+			return ret[i].ExtDB < ret[j].ExtDB
+		}
+		return ret[i].ExtID < ret[j].ExtID
+	})
+	return
+}
+
+func (s *MemStore) CreateBadSuggestion(ctx context.Context, bad *BadSuggestion) error {
+	return s.write(ctx, func(d *memData) error {
+		if err := d.checkEntryRef(bad.EntryID); err != nil {
+			return errors.Wrap(err, "CreateBadSuggestion() failed")
+		}
+		if err := checkEnum(extDBs, "ext_db", bad.ExtDB); err != nil {
+			return errors.Wrap(err, "CreateBadSuggestion() failed")
+		}
+		key := suggestionKey{bad.EntryID, bad.ExtDB, bad.ExtID}
+		if _, ok := d.badSuggestions[key]; ok {
+			return errors.Wrapf(ErrDuplicateKey,
+				"CreateBadSuggestion() failed: entry_id=%d, ext_db=%s, ext_id=%s",
+				bad.EntryID, bad.ExtDB, bad.ExtID)
+		}
+		d.badSuggestions[key] = *bad
+		return nil
+	})
+}
+
+func (s *MemStore) DeleteBadSuggestion(ctx context.Context, bad *BadSuggestion) error {
+	return s.write(ctx, func(d *memData) error {
+		delete(d.badSuggestions, suggestionKey{bad.EntryID, bad.ExtDB, bad.ExtID})
+		return nil
+	})
+}
+
+func (s *MemStore) DeleteEntryBadSuggestions(ctx context.Context, entryID int) error {
+	return s.write(ctx, func(d *memData) error {
+		for k := range d.badSuggestions {
+			if k.entryID == entryID {
+				delete(d.badSuggestions, k)
+			}
+		}
+		return nil
+	})
+}
+
+func (s *MemStore) GetAcceptedSuggestion(ctx context.Context, accepted *AcceptedSuggestion) error {
+	return s.read(ctx, func(d *memData) error {
+		stored, ok := d.accepted[accepted.EntryID]
+		if !ok {
+			return errors.Wrapf(ErrNotFound,
+				"GetAcceptedSuggestion() failed: entry_id=%d", accepted.EntryID)
+		}
+		*accepted = stored
+		return nil
+	})
+}
+
+func (s *MemStore) SaveAcceptedSuggestion(ctx context.Context, accepted *AcceptedSuggestion) error {
+	return s.write(ctx, func(d *memData) error {
+		if err := d.checkEntryRef(accepted.EntryID); err != nil {
+			return errors.Wrap(err, "SaveAcceptedSuggestion() failed")
+		}
+		if err := checkEnum(extDBs, "ext_db", accepted.ExtDB); err != nil {
+			return errors.Wrap(err, "SaveAcceptedSuggestion() failed")
+		}
+		d.accepted[accepted.EntryID] = *accepted
+		return nil
+	})
+}
+
+func (s *MemStore) DeleteEntryAcceptedSuggestion(ctx context.Context, entryID int) error {
+	return s.write(ctx, func(d *memData) error {
+		delete(d.accepted, entryID)
+		return nil
+	})
+}
+
+func (s *MemStore) SaveEntryRevision(ctx context.Context, entryID int, cmd string) error {
+	return s.write(ctx, func(d *memData) error {
+		ent, ok := d.entries[entryID]
+		if !ok {
+			return errors.Wrapf(ErrNotFound, "SaveEntryRevision() failed: entry_id=%d", entryID)
+		}
+		d.lastRevisionID++
+		rev := EntryRevision{
+			ID:           d.lastRevisionID,
+			EntryID:      ent.ID,
+			Path:         ent.Path,
+			Json:         ent.Json,
+			Status:       ent.Status,
+			LastModified: ent.LastModified,
+			Actors:       append([]*Actor{}, d.entryActors(entryID)...),
+			Cmd:          cmd,
+			CreatedAt:    time.Now().UTC()}
+		if accepted, ok := d.accepted[entryID]; ok {
+			rev.Accepted = &accepted
+		}
+		d.revisions[d.lastRevisionID] = rev
+		return nil
+	})
+}
+
+func (s *MemStore) GetEntryRevision(ctx context.Context, rev *EntryRevision) error {
+	return s.read(ctx, func(d *memData) error {
+		stored, ok := d.revisions[rev.ID]
+		if !ok {
+			return errors.Wrapf(ErrNotFound, "GetEntryRevision() failed: id=%d", rev.ID)
+		}
+		*rev = stored
+		rev.Json = cloneBytes(stored.Json)
+		cloneRevisionRefs(rev)
+		return nil
+	})
+}
+
+func (s *MemStore) EntryRevisions(
+	ctx context.Context, entryID int) (ret []*EntryRevision, err error) {
+
+	err = s.read(ctx, func(d *memData) error {
+		ret = []*EntryRevision{}
+		for _, rev := range d.revisions {
+			if rev.EntryID == entryID {
+				rev := rev
+				rev.Json, rev.Actors, rev.Accepted = nil, nil, nil
+				ret = append(ret, &rev)
+			}
+		}
+		return nil
+	})
+	sort.Slice(ret, func(i, j int) bool { return ret[i].ID > ret[j].ID })
+	return
+}
+
+func (s *MemStore) DeleteEntryRevisions(ctx context.Context, entryID int) error {
+	return s.write(ctx, func(d *memData) error {
+		for id, rev := range d.revisions {
+			if rev.EntryID == entryID {
+				delete(d.revisions, id)
+			}
+		}
+		return nil
+	})
+}
+
+func (s *MemStore) CreateEntryEvent(ctx context.Context, ev *EntryEvent) error {
+	return s.write(ctx, func(d *memData) error {
+		d.lastEventID++
+		ev.ID = d.lastEventID
+		stored := *ev
+		stored.Changed = append([]string(nil), ev.Changed...)
+		d.events = append(d.events, memEvent{ev: stored})
+		return nil
+	})
+}
+
+func (s *MemStore) PendingEntryEvents(ctx context.Context, limit int) (ret []*EntryEvent, err error) {
+	err = s.write(ctx, func(d *memData) error {
+		ret = []*EntryEvent{}
+		for _, rec := range d.events {
+			if len(ret) == limit {
+				break
+			}
+			if !rec.published {
+				ev := rec.ev
+				ev.Changed = append([]string(nil), rec.ev.Changed...)
+				ret = append(ret, &ev)
+			}
+		}
+		return nil
+	})
+	return
+}
+
+func (s *MemStore) MarkEntryEventPublished(ctx context.Context, ev *EntryEvent) error {
+	return s.write(ctx, func(d *memData) error {
+		for i := range d.events {
+			if d.events[i].ev.ID == ev.ID {
+				d.events[i].published = true
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemStoreBeginContext(t *testing.T) {
+	s := NewMemStore()
+	txctx, tx, err := s.Begin(context.Background())
+	require.NoError(t, err)
+
+	// ожидание завершения открытой транзакции ограничено контекстом
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, _, err = s.Begin(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	require.NoError(t, tx.Rollback(txctx))
+	_, tx, err = s.Begin(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit(txctx))
+}
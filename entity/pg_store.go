@@ -0,0 +1,204 @@
+package entity
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// PgStore реализует хранилище Store в БД PostgreSQL.
+type PgStore struct {
+	pool *pgxpool.Pool
+}
+
+var _ Store = (*PgStore)(nil)
+
+// NewPgStore создает хранилище, использующее пул соединений `pool`.
+func NewPgStore(pool *pgxpool.Pool) *PgStore {
+	return &PgStore{pool: pool}
+}
+
+// Возвращает контекст с пулом соединений хранилища.
+func (s *PgStore) conn(ctx context.Context) context.Context {
+	if _, err := Pool(ctx); err == nil {
+		return ctx
+	}
+	return WithPool(ctx, s.pool)
+}
+
+// Заменяет ошибку отсутствия записи pgx.ErrNoRows на ErrNotFound.
+func notFound(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errors.Wrap(ErrNotFound, err.Error())
+	}
+	return err
+}
+
+// Begin начинает транзакцию.
+func (s *PgStore) Begin(ctx context.Context) (context.Context, StoreTx, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return WithTx(s.conn(ctx), tx), tx, nil
+}
+
+func (s *PgStore) GetEntry(ctx context.Context, ent *AlbumEntry) error {
+	return notFound(ent.Get(s.conn(ctx)))
+}
+
+func (s *PgStore) CreateEntry(ctx context.Context, ent *AlbumEntry) error {
+	return ent.Create(s.conn(ctx))
+}
+
+func (s *PgStore) UpdateEntry(ctx context.Context, ent *AlbumEntry) error {
+	return notFound(ent.Update(s.conn(ctx)))
+}
+
+func (s *PgStore) DeleteEntry(ctx context.Context, ent *AlbumEntry) error {
+	return ent.Delete(s.conn(ctx))
+}
+
+func (s *PgStore) ListEntries(
+	ctx context.Context, filter *EntryFilter) ([]*AlbumEntry, string, error) {
+	return ListEntries(s.conn(ctx), filter)
+}
+
+func (s *PgStore) SearchEntries(ctx context.Context, search *EntrySearch) ([]*SearchResult, error) {
+	return SearchEntries(s.conn(ctx), search)
+}
+
+func (s *PgStore) TreeEntries(ctx context.Context, prefix string) ([]*AlbumEntry, error) {
+	return TreeEntries(s.conn(ctx), prefix)
+}
+
+func (s *PgStore) ExistingPaths(ctx context.Context, paths []string) ([]string, error) {
+	return ExistingPaths(s.conn(ctx), paths)
+}
+
+func (s *PgStore) MoveTree(ctx context.Context, oldPrefix, newPrefix string) (int64, error) {
+	return MoveTree(s.conn(ctx), oldPrefix, newPrefix)
+}
+
+func (s *PgStore) LibraryEntries(ctx context.Context) ([]*AlbumEntry, error) {
+	return LibraryEntries(s.conn(ctx))
+}
+
+func (s *PgStore) MarkEntriesStale(ctx context.Context, ids []int, since time.Time) error {
+	return MarkEntriesStale(s.conn(ctx), ids, since)
+}
+
+func (s *PgStore) EntryActors(ctx context.Context, entryID int) ([]*Actor, error) {
+	return EntryActors(s.conn(ctx), entryID)
+}
+
+func (s *PgStore) CreateActor(ctx context.Context, actor *Actor) error {
+	return actor.Create(s.conn(ctx))
+}
+
+func (s *PgStore) UpdateActor(ctx context.Context, actor *Actor) error {
+	return actor.Update(s.conn(ctx))
+}
+
+func (s *PgStore) DeleteActor(ctx context.Context, actor *Actor) error {
+	return actor.Delete(s.conn(ctx))
+}
+
+func (s *PgStore) DeleteEntryActors(ctx context.Context, entryID int) error {
+	return DeleteEntryActors(s.conn(ctx), entryID)
+}
+
+func (s *PgStore) EntryPictures(ctx context.Context, entryID int) ([]*Picture, error) {
+	return EntryPictures(s.conn(ctx), entryID)
+}
+
+func (s *PgStore) CreatePicture(ctx context.Context, pict *Picture) error {
+	return pict.Create(s.conn(ctx))
+}
+
+func (s *PgStore) DeletePicture(ctx context.Context, pict *Picture) error {
+	return pict.Delete(s.conn(ctx))
+}
+
+func (s *PgStore) DeleteEntryPictures(ctx context.Context, entryID int) error {
+	return DeleteEntryPictures(s.conn(ctx), entryID)
+}
+
+func (s *PgStore) EntrySuggestions(ctx context.Context, entryID int) ([]*Suggestion, error) {
+	return EntrySuggestions(s.conn(ctx), entryID)
+}
+
+func (s *PgStore) GetSuggestion(ctx context.Context, suggestion *Suggestion) error {
+	return notFound(suggestion.Get(s.conn(ctx)))
+}
+
+func (s *PgStore) CreateSuggestion(ctx context.Context, suggestion *Suggestion) error {
+	return suggestion.Create(s.conn(ctx))
+}
+
+func (s *PgStore) DeleteSuggestion(ctx context.Context, suggestion *Suggestion) error {
+	return suggestion.Delete(s.conn(ctx))
+}
+
+func (s *PgStore) DeleteEntrySuggestions(ctx context.Context, entryID int) error {
+	return DeleteEntrySuggestions(s.conn(ctx), entryID)
+}
+
+func (s *PgStore) EntryBadSuggestions(ctx context.Context, entryID int) ([]*BadSuggestion, error) {
+	return EntryBadSuggestions(s.conn(ctx), entryID)
+}
+
+func (s *PgStore) CreateBadSuggestion(ctx context.Context, bad *BadSuggestion) error {
+	return bad.Create(s.conn(ctx))
+}
+
+func (s *PgStore) DeleteBadSuggestion(ctx context.Context, bad *BadSuggestion) error {
+	return bad.Delete(s.conn(ctx))
+}
+
+func (s *PgStore) DeleteEntryBadSuggestions(ctx context.Context, entryID int) error {
+	return DeleteEntryBadSuggestions(s.conn(ctx), entryID)
+}
+
+func (s *PgStore) GetAcceptedSuggestion(ctx context.Context, accepted *AcceptedSuggestion) error {
+	return notFound(accepted.Get(s.conn(ctx)))
+}
+
+func (s *PgStore) SaveAcceptedSuggestion(ctx context.Context, accepted *AcceptedSuggestion) error {
+	return accepted.Save(s.conn(ctx))
+}
+
+func (s *PgStore) DeleteEntryAcceptedSuggestion(ctx context.Context, entryID int) error {
+	return DeleteEntryAcceptedSuggestion(s.conn(ctx), entryID)
+}
+
+func (s *PgStore) SaveEntryRevision(ctx context.Context, entryID int, cmd string) error {
+	return notFound(SaveEntryRevision(s.conn(ctx), entryID, cmd))
+}
+
+func (s *PgStore) GetEntryRevision(ctx context.Context, rev *EntryRevision) error {
+	return notFound(rev.Get(s.conn(ctx)))
+}
+
+func (s *PgStore) EntryRevisions(ctx context.Context, entryID int) ([]*EntryRevision, error) {
+	return EntryRevisions(s.conn(ctx), entryID)
+}
+
+func (s *PgStore) DeleteEntryRevisions(ctx context.Context, entryID int) error {
+	return DeleteEntryRevisions(s.conn(ctx), entryID)
+}
+
+func (s *PgStore) CreateEntryEvent(ctx context.Context, ev *EntryEvent) error {
+	return ev.Create(s.conn(ctx))
+}
+
+func (s *PgStore) PendingEntryEvents(ctx context.Context, limit int) ([]*EntryEvent, error) {
+	return PendingEntryEvents(s.conn(ctx), limit)
+}
+
+func (s *PgStore) MarkEntryEventPublished(ctx context.Context, ev *EntryEvent) error {
+	return ev.MarkPublished(s.conn(ctx))
+}
@@ -6,7 +6,6 @@ import (
 	"context"
 
 	"github.com/jackc/pgx/v4"
-	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
 
 	md "github.com/ytsiuryn/ds-audiomd"
@@ -37,20 +36,41 @@ func NewPicture(entType string, entID int, pict *md.PictureInAudio) *Picture {
 		Data:     pict.Data}
 }
 
+// PictureInAudio формирует из объекта изображение метаданных. Выполняет обратное NewPicture()
+// преобразование.
+func (p *Picture) PictureInAudio() *md.PictureInAudio {
+	return &md.PictureInAudio{
+		PictureMetadata: &md.PictureMetadata{
+			MimeType: p.Mime,
+			Width:    uint32(p.Width),
+			Height:   uint32(p.Height)},
+		PictType: md.StrToPictType[p.PictType],
+		Notes:    p.Notes,
+		Data:     p.Data}
+}
+
 // Pictures возвращает изображения для определенной сущности с ее ID.
-func Pictures(ctx context.Context, entType, entID int) ([]*Picture, error) {
-	db := ctx.Value("db").(*sqlx.DB)
-	if db == nil {
-		return nil, errors.Wrap(ErrConnectionInContext, "Pictures() failed")
+func Pictures(ctx context.Context, entType string, entID int) ([]*Picture, error) {
+	db, err := Conn(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Pictures() failed")
 	}
-	ret := []*Picture{}
 	qry := `SELECT * FROM audio.picture WHERE entity_type=$1 AND entity_id=$2`
-	rows, err := db.Query(qry, entType, entID)
+	rows, err := db.Query(ctx, qry, entType, entID)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Pictures() select failed")
 	}
-	if rows.Scan(&ret); err != nil {
-		return nil, errors.Wrap(err, "Pictures() scan field")
+	defer rows.Close()
+
+	ret := []*Picture{}
+	for rows.Next() {
+		var p Picture
+		err = rows.Scan(&p.EntType, &p.EntID, &p.PictType, &p.Width, &p.Height,
+			&p.Mime, &p.Notes, &p.Data)
+		if err != nil {
+			return nil, errors.Wrap(err, "Pictures() scan field")
+		}
+		ret = append(ret, &p)
 	}
 	return ret, nil
 }
@@ -73,11 +93,12 @@ func (p *Picture) Create(ctx context.Context) error {
 
 // Update обновляет данные для записи с указанным ID.
 func (p *Picture) Update(ctx context.Context) (err error) {
-	db := ctx.Value("db").(*sqlx.DB)
-	if db == nil {
-		return errors.Wrap(ErrConnectionInContext, "Picture.Update() failed")
+	tx, err := Tx(ctx)
+	if err != nil {
+		return errors.Wrap(err, "Picture.Update() failed")
 	}
-	_, err = db.Exec(
+	_, err = tx.Exec(
+		ctx,
 		`UPDATE audio.picture SET width=$1,height=$2,mime=$3,
 		notes=$4,data=$5 WHERE entity_type=$6 AND entity_id=$7 AND pict_type=$8`,
 		p.Width, p.Height, p.Mime, p.Notes, p.Data, p.EntType, p.EntID, p.PictType)
@@ -106,7 +127,7 @@ func (p *Picture) Delete(ctx context.Context) error {
 func (p *Picture) Get(ctx context.Context) error {
 	qry := `SELECT width,height,mime,notes,data
 	FROM audio.picture WHERE entity_type=$1 AND entity_id=$2 AND pict_type=$3 LIMIT 1`
-	row, err := Get(ctx, qry, p, p.EntType, p.EntID, p.PictType)
+	row, err := Get(ctx, qry, p.EntType, p.EntID, p.PictType)
 	if err != nil && err != pgx.ErrNoRows {
 		return errors.Wrap(err, "Picture.Get() select failed")
 	}
@@ -119,9 +140,9 @@ func (p *Picture) Get(ctx context.Context) error {
 
 // EntryPictures возвращает список графических объектов для Entry Assumption.
 func EntryPictures(ctx context.Context, entryID int) ([]*Picture, error) {
-	db := ctx.Value("db").(*pgx.Conn)
-	if db == nil {
-		return nil, errors.Wrap(ErrConnectionInContext, "EntryPictures() failed")
+	db, err := Conn(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "EntryPictures() failed")
 	}
 	qry := "SELECT * FROM audio.picture WHERE entity_type=$1 AND entity_id=$2"
 	rows, err := db.Query(ctx, qry, "album_entry", entryID)
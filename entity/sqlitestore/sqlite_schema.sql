@@ -0,0 +1,86 @@
+-- Схема БД SQLite, эквивалентная схеме audio PostgreSQL (см. каталог migrations).
+-- Перечисления заменены ограничениями CHECK, JSONB - текстом с проверкой json_valid(),
+-- массив идентификаторов акторов - JSON-массивом пар.
+
+CREATE TABLE album_entry (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    path VARCHAR(255) UNIQUE NOT NULL,
+    json TEXT CHECK (json IS NULL OR json_valid(json)),
+    status TEXT CHECK (status IN ('without_mandatory_tags', 'with_mandatory_tags', 'finalyzed')),
+    last_modified TIMESTAMP NOT NULL,
+    version INTEGER NOT NULL DEFAULT 1,
+    stale_since TIMESTAMP
+);
+
+CREATE TABLE suggestion (
+    entry_id INTEGER REFERENCES album_entry (id),
+    ext_db TEXT NOT NULL CHECK (ext_db IN ('rutracker', 'discogs', 'musicbrainz')),
+    ext_id VARCHAR(32) NOT NULL,
+    json TEXT CHECK (json IS NULL OR json_valid(json)),
+    score REAL,
+    PRIMARY KEY (entry_id, ext_db, ext_id)
+);
+
+CREATE TABLE bad_suggestion (
+    entry_id INTEGER REFERENCES album_entry (id),
+    ext_db TEXT NOT NULL CHECK (ext_db IN ('rutracker', 'discogs', 'musicbrainz')),
+    ext_id VARCHAR(32) NOT NULL,
+    PRIMARY KEY (entry_id, ext_db, ext_id)
+);
+
+CREATE TABLE picture (
+    entity_type TEXT NOT NULL CHECK (entity_type IN ('actor', 'work', 'composition', 'record',
+        'album_entry', 'suggestion', 'track', 'label', 'disc')),
+    entity_id INTEGER NOT NULL,
+    pict_type TEXT NOT NULL CHECK (pict_type IN ('png_icon', 'other_icon', 'cover_front',
+        'cover_back', 'leaflet', 'media', 'lad_artist', 'artist', 'conductor', 'orchestra',
+        'composer', 'lyricist', 'recording_location', 'during_recording', 'during_performance',
+        'movie_screen', 'bright_color_fish', 'illustration', 'artist_logotype',
+        'publisher_logotype')),
+    width SMALLINT,
+    height SMALLINT,
+    mime VARCHAR(20),
+    notes TEXT,
+    data BLOB,
+    PRIMARY KEY (entity_type, entity_id, pict_type)
+);
+
+CREATE TABLE actor (
+    entry_id INTEGER REFERENCES album_entry (id),
+    name VARCHAR(100) NOT NULL,
+    ids TEXT NOT NULL CHECK (json_valid(ids) AND json_type(ids) = 'array'),
+    entity_mask INTEGER,
+    PRIMARY KEY (entry_id, name)
+);
+
+CREATE TABLE accepted_suggestion (
+    entry_id INTEGER PRIMARY KEY REFERENCES album_entry (id),
+    ext_db TEXT NOT NULL CHECK (ext_db IN ('rutracker', 'discogs', 'musicbrainz')),
+    ext_id VARCHAR(32) NOT NULL,
+    merge_policy VARCHAR(20),
+    accepted_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE event_outbox (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    type VARCHAR(20) NOT NULL,
+    entry_id INTEGER NOT NULL,
+    payload TEXT NOT NULL CHECK (json_valid(payload)),
+    created_at TIMESTAMP NOT NULL,
+    published_at TIMESTAMP
+);
+CREATE INDEX idx_eventoutbox_pending ON event_outbox (id) WHERE published_at IS NULL;
+
+CREATE TABLE album_entry_revision (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    entry_id INTEGER REFERENCES album_entry (id),
+    path VARCHAR(255) NOT NULL,
+    json TEXT,
+    status TEXT CHECK (status IN ('without_mandatory_tags', 'with_mandatory_tags', 'finalyzed')),
+    last_modified TIMESTAMP NOT NULL,
+    actors TEXT CHECK (actors IS NULL OR json_valid(actors)),
+    accepted TEXT CHECK (accepted IS NULL OR json_valid(accepted)),
+    cmd VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+CREATE INDEX idx_albumentryrevision_entry ON album_entry_revision (entry_id);
@@ -0,0 +1,975 @@
+// Package sqlitestore реализует хранилище entity.Store в файле БД SQLite.
+// Пакет использует cgo (github.com/mattn/go-sqlite3) и при импорте регистрирует хранилище
+// для строк подключения вида "sqlite://<путь к файлу>":
+//
+//	import _ "github.com/ytsiuryn/ds-audiodbm/entity/sqlitestore"
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	_ "embed" // схема БД SQLite
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/pkg/errors"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+)
+
+// SchemaVersion задает версию схемы БД SQLite, хранимую в PRAGMA user_version.
+const SchemaVersion = 1
+
+// URLScheme задает префикс строки подключения к БД SQLite.
+const URLScheme = "sqlite://"
+
+// Ошибки работы с БД SQLite.
+var (
+	ErrSchemaVersion = errors.New("unexpected SQLite schema version")
+)
+
+//go:embed sqlite_schema.sql
+var sqliteSchema string
+
+// Store реализует хранилище entity.Store в БД SQLite.
+// Транзакции начинаются командой BEGIN IMMEDIATE, поэтому изменения данных выполняются
+// последовательно, а чтение вне транзакций не блокируется (журнал WAL).
+type Store struct {
+	db *sql.DB
+}
+
+var _ entity.Store = (*Store)(nil)
+
+// Методы выполнения запросов, общие для *sql.DB и *sql.Tx.
+type sqliteQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Транзакция БД SQLite.
+type sqliteTx struct {
+	s  *Store
+	tx *sql.Tx
+}
+
+type sqliteTxKey struct{}
+
+func init() {
+	entity.RegisterStore(strings.TrimSuffix(URLScheme, "://"), open)
+}
+
+// Открывает хранилище по строке подключения: при `migrate` создает схему БД,
+// затем проверяет ее версию.
+func open(ctx context.Context, path string, migrate bool) (entity.Store, error) {
+	s, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if migrate {
+		err = s.Migrate(ctx)
+	}
+	if err == nil {
+		err = s.CheckSchema(ctx)
+	}
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Open открывает файл БД SQLite `path`, создавая его при отсутствии.
+// Схема БД создается методом Migrate().
+func Open(path string) (*Store, error) {
+	dsn := "file:" + path +
+		"?_foreign_keys=1&_busy_timeout=5000&_journal_mode=WAL&_txlock=immediate"
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "sqlitestore.Open() failed: path=%s", path)
+	}
+	if err = db.Ping(); err != nil {
+		db.Close()
+		return nil, errors.Wrapf(err, "sqlitestore.Open() failed: path=%s", path)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close закрывает БД.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// SchemaVersion возвращает версию схемы БД; 0 для пустой БД.
+func (s *Store) SchemaVersion(ctx context.Context) (version int, err error) {
+	err = s.db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version)
+	if err != nil {
+		err = errors.Wrap(err, "Store.SchemaVersion() failed")
+	}
+	return
+}
+
+// Migrate создает схему БД, если она отсутствует.
+func (s *Store) Migrate(ctx context.Context) (err error) {
+	txctx, tx, err := s.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback(txctx)
+			return
+		}
+		err = tx.Commit(txctx)
+	}()
+
+	var version int
+	db := s.conn(txctx)
+	if err = db.QueryRowContext(txctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return errors.Wrap(err, "Store.Migrate() failed")
+	}
+	if version != 0 {
+		return nil
+	}
+	if _, err = db.ExecContext(txctx, sqliteSchema); err != nil {
+		return errors.Wrap(err, "Store.Migrate() failed")
+	}
+	_, err = db.ExecContext(txctx, fmt.Sprintf("PRAGMA user_version=%d", SchemaVersion))
+	if err != nil {
+		err = errors.Wrap(err, "Store.Migrate() failed")
+	}
+	return
+}
+
+// CheckSchema проверяет соответствие версии схемы БД версии SchemaVersion.
+func (s *Store) CheckSchema(ctx context.Context) error {
+	version, err := s.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if version != SchemaVersion {
+		return errors.Wrapf(ErrSchemaVersion, "version %d, expected %d",
+			version, SchemaVersion)
+	}
+	return nil
+}
+
+// Возвращает открытую транзакцию хранилища из контекста.
+func (s *Store) tx(ctx context.Context) (*sql.Tx, bool) {
+	tx, ok := ctx.Value(sqliteTxKey{}).(*sqliteTx)
+	if !ok || tx.s != s {
+		return nil, false
+	}
+	return tx.tx, true
+}
+
+// Возвращает объект для чтения данных: транзакцию контекста или БД.
+func (s *Store) conn(ctx context.Context) sqliteQuerier {
+	if tx, ok := s.tx(ctx); ok {
+		return tx
+	}
+	return s.db
+}
+
+// Возвращает транзакцию контекста для изменения данных.
+func (s *Store) writer(ctx context.Context) (sqliteQuerier, error) {
+	if tx, ok := s.tx(ctx); ok {
+		return tx, nil
+	}
+	return nil, entity.ErrTxRequired
+}
+
+// Выполняет изменяющий запрос в транзакции контекста.
+func (s *Store) exec(ctx context.Context, op, qry string, args ...interface{}) (sql.Result, error) {
+	tx, err := s.writer(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+	res, err := tx.ExecContext(ctx, qry, args...)
+	if err != nil {
+		return nil, sqliteError(err, op)
+	}
+	return res, nil
+}
+
+// Приводит ошибки SQLite к ошибкам хранилища.
+func sqliteError(err error, op string) error {
+	var sqlErr sqlite3.Error
+	switch {
+	case err == sql.ErrNoRows:
+		err = entity.ErrNotFound
+	case errors.As(err, &sqlErr):
+		switch sqlErr.ExtendedCode {
+		case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+			if strings.Contains(sqlErr.Error(), "album_entry.path") {
+				err = errors.Wrap(entity.ErrDuplicatePath, sqlErr.Error())
+			} else {
+				err = errors.Wrap(entity.ErrDuplicateKey, sqlErr.Error())
+			}
+		case sqlite3.ErrConstraintForeignKey:
+			err = errors.Wrap(entity.ErrForeignKey, sqlErr.Error())
+		case sqlite3.ErrConstraintCheck, sqlite3.ErrConstraintNotNull:
+			err = errors.Wrap(entity.ErrBadValue, sqlErr.Error())
+		}
+	}
+	return errors.Wrap(err, op)
+}
+
+// Begin начинает транзакцию.
+func (s *Store) Begin(ctx context.Context) (context.Context, entity.StoreTx, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, sqliteError(err, "Store.Begin() failed")
+	}
+	stx := &sqliteTx{s: s, tx: tx}
+	return context.WithValue(ctx, sqliteTxKey{}, stx), stx, nil
+}
+
+// Commit фиксирует изменения транзакции.
+func (tx *sqliteTx) Commit(ctx context.Context) error {
+	return tx.tx.Commit()
+}
+
+// Rollback отменяет изменения транзакции.
+func (tx *sqliteTx) Rollback(ctx context.Context) error {
+	return tx.tx.Rollback()
+}
+
+// Преобразует данные JSON в значение параметра запроса (NULL для пустых данных).
+func jsonArg(data []byte) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
+
+// Преобразует время в значение параметра запроса (NULL для nil).
+func timeArg(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// Поля записи album_entry без данных релиза.
+const sqliteEntryHeader = "id,path,status,last_modified,version"
+
+func scanEntryHeaders(rows *sql.Rows, op string, withStale bool) ([]*entity.AlbumEntry, error) {
+	defer rows.Close()
+	ret := []*entity.AlbumEntry{}
+	for rows.Next() {
+		var ent entity.AlbumEntry
+		var stale sql.NullTime
+		dest := []interface{}{&ent.ID, &ent.Path, &ent.Status, &ent.LastModified, &ent.Version}
+		if withStale {
+			dest = append(dest, &stale)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, sqliteError(err, op)
+		}
+		ent.StaleSince = nullTime(stale)
+		ret = append(ret, &ent)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, sqliteError(err, op)
+	}
+	return ret, nil
+}
+
+func (s *Store) GetEntry(ctx context.Context, ent *entity.AlbumEntry) error {
+	qry := `SELECT id,path,json,status,last_modified,version,stale_since FROM album_entry
+	WHERE id=?1 OR (?1=0 AND path=?2) LIMIT 1`
+	var data sql.NullString
+	var stale sql.NullTime
+	err := s.conn(ctx).QueryRowContext(ctx, qry, ent.ID, ent.Path).Scan(
+		&ent.ID, &ent.Path, &data, &ent.Status, &ent.LastModified, &ent.Version, &stale)
+	if err != nil {
+		return sqliteError(err, fmt.Sprintf("GetEntry() failed: id=%d", ent.ID))
+	}
+	ent.Json = nil
+	if data.Valid {
+		ent.Json = []byte(data.String)
+	}
+	ent.StaleSince = nullTime(stale)
+	return nil
+}
+
+func (s *Store) CreateEntry(ctx context.Context, ent *entity.AlbumEntry) error {
+	op := "CreateEntry() failed: path=" + ent.Path
+	res, err := s.exec(ctx, op,
+		"INSERT INTO album_entry (path,json,status,last_modified) VALUES(?,?,?,?)",
+		ent.Path, jsonArg(ent.Json), ent.Status, ent.LastModified.UTC())
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return sqliteError(err, op)
+	}
+	ent.ID, ent.Version = int(id), 1
+	return nil
+}
+
+func (s *Store) UpdateEntry(ctx context.Context, ent *entity.AlbumEntry) error {
+	op := fmt.Sprintf("UpdateEntry() failed: id=%d", ent.ID)
+	tx, err := s.writer(ctx)
+	if err != nil {
+		return errors.Wrap(err, op)
+	}
+	expected := ent.Version
+	err = tx.QueryRowContext(
+		ctx,
+		`UPDATE album_entry SET path=?1,json=?2,status=?3,last_modified=?4,version=version+1
+		WHERE id=?5 AND (?6=0 OR version=?6) RETURNING version`,
+		ent.Path, jsonArg(ent.Json), ent.Status, ent.LastModified.UTC(), ent.ID, expected).
+		Scan(&ent.Version)
+	if err == sql.ErrNoRows && expected != 0 {
+		var current int
+		err = tx.QueryRowContext(
+			ctx, "SELECT version FROM album_entry WHERE id=?", ent.ID).Scan(&current)
+		if err == nil {
+			return errors.Wrapf(entity.ErrVersionConflict, "%s, version=%d, current version=%d",
+				op, expected, current)
+		}
+	}
+	if err != nil {
+		return sqliteError(err, op)
+	}
+	return nil
+}
+
+func (s *Store) DeleteEntry(ctx context.Context, ent *entity.AlbumEntry) error {
+	_, err := s.exec(ctx, fmt.Sprintf("DeleteEntry() failed: id=%d", ent.ID),
+		"DELETE FROM album_entry WHERE id=?1 OR (?1=0 AND path=?2)", ent.ID, ent.Path)
+	return err
+}
+
+func (s *Store) ListEntries(
+	ctx context.Context, filter *entity.EntryFilter) (_ []*entity.AlbumEntry, next string, err error) {
+
+	qry, args, err := listQuery(filter)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "ListEntries() failed")
+	}
+	rows, err := s.conn(ctx).QueryContext(ctx, qry, args...)
+	if err != nil {
+		return nil, "", sqliteError(err, "ListEntries() select failed")
+	}
+	ret, err := scanEntryHeaders(rows, "ListEntries() scan failed", false)
+	if err != nil {
+		return nil, "", err
+	}
+	if limit := filter.PageLimit(); len(ret) > limit {
+		ret = ret[:limit]
+		next = filter.NextCursor(ret[limit-1])
+	}
+	return ret, next, nil
+}
+
+// Формирует текст запроса списка Entry для SQLite и его параметры.
+func listQuery(f *entity.EntryFilter) (string, []interface{}, error) {
+	c, err := f.Position()
+	if err != nil {
+		return "", nil, err
+	}
+
+	var where []string
+	var args []interface{}
+	if f.Status != "" {
+		where, args = append(where, "status=?"), append(args, f.Status)
+	}
+	if f.PathPrefix != "" {
+		where = append(where, `path LIKE ? ESCAPE '\'`)
+		args = append(args, entity.EscapeLike(f.PathPrefix)+"%")
+	}
+	if f.ModifiedAfter != nil {
+		where, args = append(where, "last_modified>=?"), append(args, f.ModifiedAfter.UTC())
+	}
+	if f.ModifiedBefore != nil {
+		where, args = append(where, "last_modified<?"), append(args, f.ModifiedBefore.UTC())
+	}
+	if f.HasSuggestions != nil {
+		cond := "EXISTS (SELECT 1 FROM suggestion s WHERE s.entry_id=e.id)"
+		if !*f.HasSuggestions {
+			cond = "NOT " + cond
+		}
+		where = append(where, cond)
+	}
+
+	sortBy := f.SortField()
+	op, dir := ">", "ASC"
+	if f.Desc {
+		op, dir = "<", "DESC"
+	}
+	if c != nil {
+		switch sortBy {
+		case entity.SortByID:
+			where, args = append(where, "id"+op+"?"), append(args, c.ID)
+		case entity.SortByPath:
+			where = append(where, "(path,id)"+op+"(?,?)")
+			args = append(args, c.Value, c.ID)
+		case entity.SortByLastModified:
+			where = append(where, "(last_modified,id)"+op+"(?,?)")
+			args = append(args, c.Modified.UTC(), c.ID)
+		}
+	}
+
+	qry := "SELECT " + sqliteEntryHeader + " FROM album_entry e"
+	if len(where) > 0 {
+		qry += " WHERE " + strings.Join(where, " AND ")
+	}
+	if sortBy == entity.SortByID {
+		qry += " ORDER BY id " + dir
+	} else {
+		qry += fmt.Sprintf(" ORDER BY %s %s,id %s", sortBy, dir, dir)
+	}
+	qry += " LIMIT ?"
+	args = append(args, f.PageLimit()+1)
+
+	return qry, args, nil
+}
+
+// SearchEntries выполняет поиск каталогов по метаданным релизов.
+// Условия точного совпадения проверяются функциями json1, полнотекстовое условие -
+// так же, как в entity.MemStore.
+func (s *Store) SearchEntries(ctx context.Context, search *entity.EntrySearch) ([]*entity.SearchResult, error) {
+	q, err := search.Matcher()
+	if err != nil {
+		return nil, errors.Wrap(err, "SearchEntries() failed")
+	}
+
+	where := []string{"json IS NOT NULL"}
+	var args []interface{}
+	if search.Genre != "" {
+		where = append(where, `EXISTS (SELECT 1 FROM json_each(e.json,'$.tracks') t,
+			json_each(t.value,'$.record.genres') g WHERE g.value=?)`)
+		args = append(args, search.Genre)
+	}
+	if search.Label != "" {
+		where = append(where, `EXISTS (SELECT 1 FROM json_each(e.json,'$.publishing') p
+			WHERE json_extract(p.value,'$.name')=?)`)
+		args = append(args, search.Label)
+	}
+	if search.Catno != "" {
+		where = append(where, `EXISTS (SELECT 1 FROM json_each(e.json,'$.publishing') p
+			WHERE json_extract(p.value,'$.catno')=?)`)
+		args = append(args, search.Catno)
+	}
+
+	rows, err := s.conn(ctx).QueryContext(ctx,
+		"SELECT "+sqliteEntryHeader+",json FROM album_entry e WHERE "+strings.Join(where, " AND "),
+		args...)
+	if err != nil {
+		return nil, sqliteError(err, "SearchEntries() select failed")
+	}
+	defer rows.Close()
+
+	ret := []*entity.SearchResult{}
+	for rows.Next() {
+		var ent entity.AlbumEntry
+		var data string
+		err = rows.Scan(&ent.ID, &ent.Path, &ent.Status, &ent.LastModified, &ent.Version, &data)
+		if err != nil {
+			return nil, sqliteError(err, "SearchEntries() scan failed")
+		}
+		ent.Json = []byte(data)
+		if res := q.Match(&ent); res != nil {
+			ret = append(ret, res)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, sqliteError(err, "SearchEntries() select failed")
+	}
+	return search.Page(ret), nil
+}
+
+func (s *Store) TreeEntries(ctx context.Context, prefix string) ([]*entity.AlbumEntry, error) {
+	rows, err := s.conn(ctx).QueryContext(ctx,
+		"SELECT "+sqliteEntryHeader+` FROM album_entry
+		WHERE path=?1 OR path LIKE ?2 ESCAPE '\' ORDER BY path`,
+		prefix, entity.EscapeLike(prefix)+"/%")
+	if err != nil {
+		return nil, sqliteError(err, "TreeEntries() select failed")
+	}
+	return scanEntryHeaders(rows, "TreeEntries() scan failed", false)
+}
+
+func (s *Store) ExistingPaths(ctx context.Context, paths []string) ([]string, error) {
+	data, err := json.Marshal(paths)
+	if err != nil {
+		return nil, errors.Wrap(err, "ExistingPaths() failed")
+	}
+	rows, err := s.conn(ctx).QueryContext(ctx,
+		"SELECT path FROM album_entry WHERE path IN (SELECT value FROM json_each(?)) ORDER BY path",
+		string(data))
+	if err != nil {
+		return nil, sqliteError(err, "ExistingPaths() select failed")
+	}
+	defer rows.Close()
+
+	var ret []string
+	for rows.Next() {
+		var path string
+		if err = rows.Scan(&path); err != nil {
+			return nil, sqliteError(err, "ExistingPaths() scan failed")
+		}
+		ret = append(ret, path)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, sqliteError(err, "ExistingPaths() select failed")
+	}
+	return ret, nil
+}
+
+func (s *Store) MoveTree(ctx context.Context, oldPrefix, newPrefix string) (int64, error) {
+	res, err := s.exec(ctx, fmt.Sprintf("MoveTree() failed: %q -> %q", oldPrefix, newPrefix),
+		`UPDATE album_entry SET path=?3||substr(path,length(?1)+1),version=version+1
+		WHERE path=?1 OR path LIKE ?2 ESCAPE '\'`,
+		oldPrefix, entity.EscapeLike(oldPrefix)+"/%", newPrefix)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (s *Store) LibraryEntries(ctx context.Context) ([]*entity.AlbumEntry, error) {
+	rows, err := s.conn(ctx).QueryContext(ctx,
+		"SELECT "+sqliteEntryHeader+",stale_since FROM album_entry ORDER BY path")
+	if err != nil {
+		return nil, sqliteError(err, "LibraryEntries() select failed")
+	}
+	return scanEntryHeaders(rows, "LibraryEntries() scan failed", true)
+}
+
+func (s *Store) MarkEntriesStale(ctx context.Context, ids []int, since time.Time) error {
+	data, err := json.Marshal(ids)
+	if err != nil {
+		return errors.Wrap(err, "MarkEntriesStale() failed")
+	}
+	var arg *time.Time
+	if !since.IsZero() {
+		arg = &since
+	}
+	_, err = s.exec(ctx, "MarkEntriesStale() failed",
+		"UPDATE album_entry SET stale_since=? WHERE id IN (SELECT value FROM json_each(?))",
+		timeArg(arg), string(data))
+	return err
+}
+
+func (s *Store) EntryActors(ctx context.Context, entryID int) ([]*entity.Actor, error) {
+	rows, err := s.conn(ctx).QueryContext(ctx,
+		"SELECT entry_id,name,ids,entity_mask FROM actor WHERE entry_id=? ORDER BY name", entryID)
+	if err != nil {
+		return nil, sqliteError(err, "EntryActors() select failed")
+	}
+	defer rows.Close()
+
+	var ret []*entity.Actor
+	for rows.Next() {
+		var actor entity.Actor
+		var ids string
+		if err = rows.Scan(&actor.EntryID, &actor.Name, &ids, &actor.EntityMask); err != nil {
+			return nil, sqliteError(err, "EntryActors() scan failed")
+		}
+		if err = json.Unmarshal([]byte(ids), &actor.IDs); err != nil {
+			return nil, errors.Wrap(err, "EntryActors() ids decoding failed")
+		}
+		ret = append(ret, &actor)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, sqliteError(err, "EntryActors() select failed")
+	}
+	return ret, nil
+}
+
+// Возвращает идентификаторы актора в виде JSON-массива пар.
+func actorIDsArg(actor *entity.Actor) (string, error) {
+	ids := actor.IDs
+	if ids == nil {
+		ids = [][2]string{}
+	}
+	data, err := json.Marshal(ids)
+	return string(data), err
+}
+
+func (s *Store) CreateActor(ctx context.Context, actor *entity.Actor) error {
+	op := fmt.Sprintf("CreateActor() failed: entry_id=%d, name=%s", actor.EntryID, actor.Name)
+	ids, err := actorIDsArg(actor)
+	if err != nil {
+		return errors.Wrap(err, op)
+	}
+	_, err = s.exec(ctx, op,
+		"INSERT INTO actor (entry_id,name,ids,entity_mask) VALUES(?,?,?,?)",
+		actor.EntryID, actor.Name, ids, actor.EntityMask)
+	return err
+}
+
+func (s *Store) UpdateActor(ctx context.Context, actor *entity.Actor) error {
+	op := fmt.Sprintf("UpdateActor() failed: entry_id=%d, name=%s", actor.EntryID, actor.Name)
+	ids, err := actorIDsArg(actor)
+	if err != nil {
+		return errors.Wrap(err, op)
+	}
+	_, err = s.exec(ctx, op,
+		"UPDATE actor SET ids=?,entity_mask=? WHERE entry_id=? AND name=?",
+		ids, actor.EntityMask, actor.EntryID, actor.Name)
+	return err
+}
+
+func (s *Store) DeleteActor(ctx context.Context, actor *entity.Actor) error {
+	_, err := s.exec(ctx, "DeleteActor() failed",
+		"DELETE FROM actor WHERE entry_id=? AND name=?", actor.EntryID, actor.Name)
+	return err
+}
+
+func (s *Store) DeleteEntryActors(ctx context.Context, entryID int) error {
+	_, err := s.exec(ctx, "DeleteEntryActors() failed",
+		"DELETE FROM actor WHERE entry_id=?", entryID)
+	return err
+}
+
+func (s *Store) EntryPictures(ctx context.Context, entryID int) ([]*entity.Picture, error) {
+	rows, err := s.conn(ctx).QueryContext(ctx,
+		`SELECT entity_type,entity_id,pict_type,width,height,mime,notes,data FROM picture
+		WHERE entity_type='album_entry' AND entity_id=? ORDER BY pict_type`,
+		entryID)
+	if err != nil {
+		return nil, sqliteError(err, fmt.Sprintf("EntryPictures() select failed: entry_id=%d", entryID))
+	}
+	defer rows.Close()
+
+	ret := []*entity.Picture{}
+	for rows.Next() {
+		var p entity.Picture
+		err = rows.Scan(&p.EntType, &p.EntID, &p.PictType, &p.Width, &p.Height,
+			&p.Mime, &p.Notes, &p.Data)
+		if err != nil {
+			return nil, sqliteError(err, "EntryPictures() scan failed")
+		}
+		ret = append(ret, &p)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, sqliteError(err, "EntryPictures() select failed")
+	}
+	return ret, nil
+}
+
+func (s *Store) CreatePicture(ctx context.Context, p *entity.Picture) error {
+	_, err := s.exec(ctx,
+		fmt.Sprintf("CreatePicture() failed: entity_type=%s, entity_id=%d, pict_type=%s",
+			p.EntType, p.EntID, p.PictType),
+		`INSERT INTO picture (entity_type,entity_id,pict_type,width,height,mime,notes,data)
+		VALUES (?,?,?,?,?,?,?,?)`,
+		p.EntType, p.EntID, p.PictType, p.Width, p.Height, p.Mime, p.Notes, p.Data)
+	return err
+}
+
+func (s *Store) DeletePicture(ctx context.Context, p *entity.Picture) error {
+	_, err := s.exec(ctx, "DeletePicture() failed",
+		"DELETE FROM picture WHERE entity_type=? AND entity_id=? AND pict_type=?",
+		p.EntType, p.EntID, p.PictType)
+	return err
+}
+
+func (s *Store) DeleteEntryPictures(ctx context.Context, entryID int) error {
+	_, err := s.exec(ctx, "DeleteEntryPictures() failed",
+		"DELETE FROM picture WHERE entity_type='album_entry' AND entity_id=?", entryID)
+	return err
+}
+
+func (s *Store) EntrySuggestions(ctx context.Context, entryID int) ([]*entity.Suggestion, error) {
+	rows, err := s.conn(ctx).QueryContext(ctx,
+		`SELECT entry_id,ext_db,ext_id,json,coalesce(score,0) FROM suggestion
+		WHERE entry_id=? ORDER BY ext_db,ext_id`,
+		entryID)
+	if err != nil {
+		return nil, sqliteError(err, "EntrySuggestions() select failed")
+	}
+	defer rows.Close()
+
+	ret := []*entity.Suggestion{}
+	for rows.Next() {
+		var s entity.Suggestion
+		var data sql.NullString
+		if err = rows.Scan(&s.EntryID, &s.ExtDB, &s.ExtID, &data, &s.Score); err != nil {
+			return nil, sqliteError(err, "EntrySuggestions() scan failed")
+		}
+		if data.Valid {
+			s.Json = []byte(data.String)
+		}
+		ret = append(ret, &s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, sqliteError(err, "EntrySuggestions() select failed")
+	}
+	return ret, nil
+}
+
+func (s *Store) GetSuggestion(ctx context.Context, suggestion *entity.Suggestion) error {
+	var data sql.NullString
+	err := s.conn(ctx).QueryRowContext(ctx,
+		`SELECT json,coalesce(score,0) FROM suggestion
+		WHERE entry_id=? AND ext_db=? AND ext_id=? LIMIT 1`,
+		suggestion.EntryID, suggestion.ExtDB, suggestion.ExtID).Scan(&data, &suggestion.Score)
+	if err != nil {
+		return sqliteError(err, fmt.Sprintf(
+			"GetSuggestion() failed: entry_id=%d, ext_db=%s, ext_id=%s",
+			suggestion.EntryID, suggestion.ExtDB, suggestion.ExtID))
+	}
+	suggestion.Json = nil
+	if data.Valid {
+		suggestion.Json = []byte(data.String)
+	}
+	return nil
+}
+
+func (s *Store) CreateSuggestion(ctx context.Context, suggestion *entity.Suggestion) error {
+	_, err := s.exec(ctx,
+		fmt.Sprintf("CreateSuggestion() failed: entry_id=%d, ext_db=%s, ext_id=%s",
+			suggestion.EntryID, suggestion.ExtDB, suggestion.ExtID),
+		"INSERT INTO suggestion (entry_id,ext_db,ext_id,json,score) VALUES(?,?,?,?,?)",
+		suggestion.EntryID, suggestion.ExtDB, suggestion.ExtID, jsonArg(suggestion.Json),
+		suggestion.Score)
+	return err
+}
+
+func (s *Store) DeleteSuggestion(ctx context.Context, suggestion *entity.Suggestion) error {
+	_, err := s.exec(ctx, "DeleteSuggestion() failed",
+		"DELETE FROM suggestion WHERE entry_id=? AND ext_db=? AND ext_id=?",
+		suggestion.EntryID, suggestion.ExtDB, suggestion.ExtID)
+	return err
+}
+
+func (s *Store) DeleteEntrySuggestions(ctx context.Context, entryID int) error {
+	_, err := s.exec(ctx, "DeleteEntrySuggestions() failed",
+		"DELETE FROM suggestion WHERE entry_id=?", entryID)
+	return err
+}
+
+func (s *Store) EntryBadSuggestions(ctx context.Context, entryID int) ([]*entity.BadSuggestion, error) {
+	rows, err := s.conn(ctx).QueryContext(ctx,
+		"SELECT entry_id,ext_db,ext_id FROM bad_suggestion WHERE entry_id=? ORDER BY ext_db,ext_id",
+		entryID)
+	if err != nil {
+		return nil, sqliteError(err, "EntryBadSuggestions() select failed")
+	}
+	defer rows.Close()
+
+	ret := []*entity.BadSuggestion{}
+	for rows.Next() {
+		var bs entity.BadSuggestion
+		if err = rows.Scan(&bs.EntryID, &bs.ExtDB, &bs.ExtID); err != nil {
+			return nil, sqliteError(err, "EntryBadSuggestions() scan failed")
+		}
+		ret = append(ret, &bs)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, sqliteError(err, "EntryBadSuggestions() select failed")
+	}
+	return ret, nil
+}
+
+func (s *Store) CreateBadSuggestion(ctx context.Context, bad *entity.BadSuggestion) error {
+	_, err := s.exec(ctx,
+		fmt.Sprintf("CreateBadSuggestion() failed: entry_id=%d, ext_db=%s, ext_id=%s",
+			bad.EntryID, bad.ExtDB, bad.ExtID),
+		"INSERT INTO bad_suggestion (entry_id,ext_db,ext_id) VALUES(?,?,?)",
+		bad.EntryID, bad.ExtDB, bad.ExtID)
+	return err
+}
+
+func (s *Store) DeleteBadSuggestion(ctx context.Context, bad *entity.BadSuggestion) error {
+	_, err := s.exec(ctx, "DeleteBadSuggestion() failed",
+		"DELETE FROM bad_suggestion WHERE entry_id=? AND ext_db=? AND ext_id=?",
+		bad.EntryID, bad.ExtDB, bad.ExtID)
+	return err
+}
+
+func (s *Store) DeleteEntryBadSuggestions(ctx context.Context, entryID int) error {
+	_, err := s.exec(ctx, "DeleteEntryBadSuggestions() failed",
+		"DELETE FROM bad_suggestion WHERE entry_id=?", entryID)
+	return err
+}
+
+func (s *Store) GetAcceptedSuggestion(ctx context.Context, as *entity.AcceptedSuggestion) error {
+	var policy sql.NullString
+	err := s.conn(ctx).QueryRowContext(ctx,
+		`SELECT ext_db,ext_id,merge_policy,accepted_at FROM accepted_suggestion
+		WHERE entry_id=? LIMIT 1`,
+		as.EntryID).Scan(&as.ExtDB, &as.ExtID, &policy, &as.AcceptedAt)
+	if err != nil {
+		return sqliteError(err,
+			fmt.Sprintf("GetAcceptedSuggestion() failed: entry_id=%d", as.EntryID))
+	}
+	as.MergePolicy = policy.String
+	return nil
+}
+
+func (s *Store) SaveAcceptedSuggestion(ctx context.Context, as *entity.AcceptedSuggestion) error {
+	_, err := s.exec(ctx,
+		fmt.Sprintf("SaveAcceptedSuggestion() failed: entry_id=%d, ext_db=%s, ext_id=%s",
+			as.EntryID, as.ExtDB, as.ExtID),
+		`INSERT INTO accepted_suggestion (entry_id,ext_db,ext_id,merge_policy,accepted_at)
+		VALUES(?,?,?,?,?)
+		ON CONFLICT (entry_id) DO UPDATE SET ext_db=excluded.ext_db,ext_id=excluded.ext_id,
+		merge_policy=excluded.merge_policy,accepted_at=excluded.accepted_at`,
+		as.EntryID, as.ExtDB, as.ExtID, as.MergePolicy, as.AcceptedAt.UTC())
+	return err
+}
+
+func (s *Store) DeleteEntryAcceptedSuggestion(ctx context.Context, entryID int) error {
+	_, err := s.exec(ctx, "DeleteEntryAcceptedSuggestion() failed",
+		"DELETE FROM accepted_suggestion WHERE entry_id=?", entryID)
+	return err
+}
+
+func (s *Store) SaveEntryRevision(ctx context.Context, entryID int, cmd string) error {
+	op := fmt.Sprintf("SaveEntryRevision() failed: entry_id=%d", entryID)
+	rev := &entity.EntryRevision{EntryID: entryID}
+	var err error
+	if rev.Actors, err = s.EntryActors(ctx, entryID); err != nil {
+		return errors.Wrap(err, op)
+	}
+	accepted := &entity.AcceptedSuggestion{EntryID: entryID}
+	if err = s.GetAcceptedSuggestion(ctx, accepted); err == nil {
+		rev.Accepted = accepted
+	} else if !errors.Is(err, entity.ErrNotFound) {
+		return errors.Wrap(err, op)
+	}
+	actors, acceptedData, err := rev.MarshalRefs()
+	if err != nil {
+		return err
+	}
+	res, err := s.exec(ctx, op,
+		`INSERT INTO album_entry_revision
+		(entry_id,path,json,status,last_modified,actors,accepted,cmd,created_at)
+		SELECT id,path,json,status,last_modified,?4,?5,?2,?3 FROM album_entry WHERE id=?1`,
+		entryID, cmd, time.Now().UTC(), jsonArg(actors), jsonArg(acceptedData))
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return sqliteError(sql.ErrNoRows, op)
+	}
+	return nil
+}
+
+func (s *Store) GetEntryRevision(ctx context.Context, r *entity.EntryRevision) error {
+	var data, actors, accepted sql.NullString
+	err := s.conn(ctx).QueryRowContext(ctx,
+		`SELECT entry_id,path,json,status,last_modified,actors,accepted,cmd,created_at
+		FROM album_entry_revision WHERE id=? LIMIT 1`,
+		r.ID).Scan(&r.EntryID, &r.Path, &data, &r.Status, &r.LastModified, &actors, &accepted,
+		&r.Cmd, &r.CreatedAt)
+	if err != nil {
+		return sqliteError(err, fmt.Sprintf("GetEntryRevision() failed: id=%d", r.ID))
+	}
+	r.Json = nil
+	if data.Valid {
+		r.Json = []byte(data.String)
+	}
+	return r.UnmarshalRefs([]byte(actors.String), []byte(accepted.String))
+}
+
+func (s *Store) EntryRevisions(ctx context.Context, entryID int) ([]*entity.EntryRevision, error) {
+	rows, err := s.conn(ctx).QueryContext(ctx,
+		`SELECT id,entry_id,path,status,last_modified,cmd,created_at
+		FROM album_entry_revision WHERE entry_id=? ORDER BY id DESC`,
+		entryID)
+	if err != nil {
+		return nil, sqliteError(err, "EntryRevisions() select failed")
+	}
+	defer rows.Close()
+
+	ret := []*entity.EntryRevision{}
+	for rows.Next() {
+		var r entity.EntryRevision
+		err = rows.Scan(&r.ID, &r.EntryID, &r.Path, &r.Status, &r.LastModified, &r.Cmd, &r.CreatedAt)
+		if err != nil {
+			return nil, sqliteError(err, "EntryRevisions() scan failed")
+		}
+		ret = append(ret, &r)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, sqliteError(err, "EntryRevisions() select failed")
+	}
+	return ret, nil
+}
+
+func (s *Store) DeleteEntryRevisions(ctx context.Context, entryID int) error {
+	_, err := s.exec(ctx, "DeleteEntryRevisions() failed",
+		"DELETE FROM album_entry_revision WHERE entry_id=?", entryID)
+	return err
+}
+
+func (s *Store) CreateEntryEvent(ctx context.Context, ev *entity.EntryEvent) error {
+	op := fmt.Sprintf("CreateEntryEvent() failed: type=%s, entry_id=%d", ev.Type, ev.EntryID)
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return errors.Wrap(err, op)
+	}
+	res, err := s.exec(ctx, op,
+		"INSERT INTO event_outbox (type,entry_id,payload,created_at) VALUES(?,?,?,?)",
+		ev.Type, ev.EntryID, string(payload), ev.CreatedAt.UTC())
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return sqliteError(err, op)
+	}
+	ev.ID = int(id)
+	return nil
+}
+
+// PendingEntryEvents возвращает до `limit` неопубликованных событий в порядке их создания.
+// Транзакция SQLite блокирует запись в БД до ее завершения, поэтому события не могут быть
+// выбраны повторно другим экземпляром сервиса.
+func (s *Store) PendingEntryEvents(ctx context.Context, limit int) ([]*entity.EntryEvent, error) {
+	tx, err := s.writer(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "PendingEntryEvents() failed")
+	}
+	rows, err := tx.QueryContext(ctx,
+		"SELECT id,payload FROM event_outbox WHERE published_at IS NULL ORDER BY id LIMIT ?",
+		limit)
+	if err != nil {
+		return nil, sqliteError(err, "PendingEntryEvents() select failed")
+	}
+	defer rows.Close()
+
+	ret := []*entity.EntryEvent{}
+	for rows.Next() {
+		var id int
+		var payload string
+		if err = rows.Scan(&id, &payload); err != nil {
+			return nil, sqliteError(err, "PendingEntryEvents() scan failed")
+		}
+		var ev entity.EntryEvent
+		if err = json.Unmarshal([]byte(payload), &ev); err != nil {
+			return nil, errors.Wrapf(err, "PendingEntryEvents() payload decoding failed: id=%d", id)
+		}
+		ev.ID = id
+		ret = append(ret, &ev)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, sqliteError(err, "PendingEntryEvents() select failed")
+	}
+	return ret, nil
+}
+
+func (s *Store) MarkEntryEventPublished(ctx context.Context, ev *entity.EntryEvent) error {
+	_, err := s.exec(ctx, fmt.Sprintf("MarkEntryEventPublished() failed: id=%d", ev.ID),
+		"UPDATE event_outbox SET published_at=? WHERE id=?", time.Now().UTC(), ev.ID)
+	return err
+}
@@ -0,0 +1,28 @@
+package sqlitestore
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity/storetest"
+)
+
+// Создает хранилище SQLite во временном каталоге и проверяет миграцию его схемы.
+func newTestStore(t *testing.T) *Store {
+	s, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+	assert.ErrorIs(t, s.CheckSchema(context.Background()), ErrSchemaVersion)
+	require.NoError(t, s.Migrate(context.Background()))
+	require.NoError(t, s.Migrate(context.Background()))
+	require.NoError(t, s.CheckSchema(context.Background()))
+	return s
+}
+
+func TestStore(t *testing.T) {
+	storetest.Run(t, newTestStore(t))
+}
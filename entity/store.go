@@ -0,0 +1,162 @@
+package entity
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Ошибки нарушения ограничений целостности данных хранилищ, не использующих PostgreSQL.
+// PgStore в этих случаях возвращает ошибки PostgreSQL (pgconn.PgError).
+var (
+	ErrDuplicateKey  = errors.New("duplicate key value violates unique constraint")
+	ErrDuplicatePath = errors.Wrap(ErrDuplicateKey, "entry path already exists")
+	ErrForeignKey    = errors.New("violates foreign key constraint")
+	ErrBadValue      = errors.New("violates check or not-null constraint")
+)
+
+// ErrNotFound возвращается хранилищами при отсутствии запрашиваемой записи.
+var ErrNotFound = errors.New("record not found")
+
+// ErrUnknownStore возвращается OpenStore() для строки подключения, схема которой
+// не зарегистрирована.
+var ErrUnknownStore = errors.New("no store registered for the URL scheme")
+
+// StoreOpener открывает хранилище по строке подключения без префикса "<scheme>://".
+// При `migrate` отсутствующая схема БД создается; версия схемы БД проверяется всегда.
+type StoreOpener func(ctx context.Context, dsn string, migrate bool) (Store, error)
+
+var (
+	openersMu sync.RWMutex
+	openers   = map[string]StoreOpener{}
+)
+
+// RegisterStore регистрирует хранилище для строк подключения вида "<scheme>://<dsn>".
+// Вызывается из функций init() пакетов хранилищ (см. entity/sqlitestore), поэтому
+// зависимости хранилища подключаются к программе только при импорте его пакета.
+func RegisterStore(scheme string, open StoreOpener) {
+	openersMu.Lock()
+	defer openersMu.Unlock()
+	if _, dup := openers[scheme]; dup {
+		panic("entity: RegisterStore called twice for scheme " + scheme)
+	}
+	openers[scheme] = open
+}
+
+// OpenStore открывает хранилище, зарегистрированное для схемы строки подключения `dbURL`.
+// Хранилище, владеющее ресурсами, реализует io.Closer.
+func OpenStore(ctx context.Context, dbURL string, migrate bool) (Store, error) {
+	scheme := ""
+	if i := strings.Index(dbURL, "://"); i > 0 {
+		scheme = dbURL[:i]
+	}
+	openersMu.RLock()
+	open, ok := openers[scheme]
+	openersMu.RUnlock()
+	if !ok {
+		return nil, errors.Wrapf(ErrUnknownStore, "scheme=%q", scheme)
+	}
+	return open(ctx, dbURL[len(scheme)+len("://"):], migrate)
+}
+
+// Store описывает хранилище данных каталогов аудио-библиотеки.
+// Изменяющие операции выполняются в транзакции: контекст операции должен быть получен
+// от Begin(). Операции чтения с таким контекстом также выполняются в транзакции.
+type Store interface {
+	TxStore
+	EntryStore
+	ActorStore
+	PictureStore
+	SuggestionStore
+	BadSuggestionStore
+	AcceptedSuggestionStore
+	RevisionStore
+	EventStore
+}
+
+// StoreTx описывает транзакцию хранилища.
+type StoreTx interface {
+	Commit(ctx context.Context) error
+	Rollback(ctx context.Context) error
+}
+
+// TxStore описывает управление транзакциями хранилища.
+type TxStore interface {
+	// Begin начинает транзакцию и возвращает контекст для выполнения в ней операций хранилища.
+	Begin(ctx context.Context) (context.Context, StoreTx, error)
+}
+
+// EntryStore описывает операции с записями каталогов.
+// При отсутствии записи возвращается ошибка, совместимая через errors.Is() с ErrNotFound.
+type EntryStore interface {
+	GetEntry(ctx context.Context, ent *AlbumEntry) error
+	CreateEntry(ctx context.Context, ent *AlbumEntry) error
+	UpdateEntry(ctx context.Context, ent *AlbumEntry) error
+	DeleteEntry(ctx context.Context, ent *AlbumEntry) error
+	ListEntries(ctx context.Context, filter *EntryFilter) ([]*AlbumEntry, string, error)
+	SearchEntries(ctx context.Context, search *EntrySearch) ([]*SearchResult, error)
+	TreeEntries(ctx context.Context, prefix string) ([]*AlbumEntry, error)
+	ExistingPaths(ctx context.Context, paths []string) ([]string, error)
+	MoveTree(ctx context.Context, oldPrefix, newPrefix string) (int64, error)
+	LibraryEntries(ctx context.Context) ([]*AlbumEntry, error)
+	MarkEntriesStale(ctx context.Context, ids []int, since time.Time) error
+}
+
+// ActorStore описывает операции с акторами каталогов.
+type ActorStore interface {
+	EntryActors(ctx context.Context, entryID int) ([]*Actor, error)
+	CreateActor(ctx context.Context, actor *Actor) error
+	UpdateActor(ctx context.Context, actor *Actor) error
+	DeleteActor(ctx context.Context, actor *Actor) error
+	DeleteEntryActors(ctx context.Context, entryID int) error
+}
+
+// PictureStore описывает операции с графическими объектами каталогов.
+type PictureStore interface {
+	EntryPictures(ctx context.Context, entryID int) ([]*Picture, error)
+	CreatePicture(ctx context.Context, pict *Picture) error
+	DeletePicture(ctx context.Context, pict *Picture) error
+	DeleteEntryPictures(ctx context.Context, entryID int) error
+}
+
+// SuggestionStore описывает операции с online-предложениями.
+type SuggestionStore interface {
+	EntrySuggestions(ctx context.Context, entryID int) ([]*Suggestion, error)
+	GetSuggestion(ctx context.Context, suggestion *Suggestion) error
+	CreateSuggestion(ctx context.Context, suggestion *Suggestion) error
+	DeleteSuggestion(ctx context.Context, suggestion *Suggestion) error
+	DeleteEntrySuggestions(ctx context.Context, entryID int) error
+}
+
+// BadSuggestionStore описывает операции с отвергнутыми online-предложениями.
+type BadSuggestionStore interface {
+	EntryBadSuggestions(ctx context.Context, entryID int) ([]*BadSuggestion, error)
+	CreateBadSuggestion(ctx context.Context, bad *BadSuggestion) error
+	DeleteBadSuggestion(ctx context.Context, bad *BadSuggestion) error
+	DeleteEntryBadSuggestions(ctx context.Context, entryID int) error
+}
+
+// AcceptedSuggestionStore описывает операции со сведениями о принятых предложениях.
+type AcceptedSuggestionStore interface {
+	GetAcceptedSuggestion(ctx context.Context, accepted *AcceptedSuggestion) error
+	SaveAcceptedSuggestion(ctx context.Context, accepted *AcceptedSuggestion) error
+	DeleteEntryAcceptedSuggestion(ctx context.Context, entryID int) error
+}
+
+// RevisionStore описывает операции с ревизиями каталогов.
+type RevisionStore interface {
+	SaveEntryRevision(ctx context.Context, entryID int, cmd string) error
+	GetEntryRevision(ctx context.Context, rev *EntryRevision) error
+	EntryRevisions(ctx context.Context, entryID int) ([]*EntryRevision, error)
+	DeleteEntryRevisions(ctx context.Context, entryID int) error
+}
+
+// EventStore описывает операции с событиями изменения каталогов.
+type EventStore interface {
+	CreateEntryEvent(ctx context.Context, ev *EntryEvent) error
+	PendingEntryEvents(ctx context.Context, limit int) ([]*EntryEvent, error)
+	MarkEntryEventPublished(ctx context.Context, ev *EntryEvent) error
+}
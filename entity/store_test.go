@@ -0,0 +1,55 @@
+package entity_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+	"github.com/ytsiuryn/ds-audiodbm/entity/storetest"
+	"github.com/ytsiuryn/ds-audiodbm/migrations"
+)
+
+func TestMemStore(t *testing.T) {
+	storetest.Run(t, entity.NewMemStore())
+}
+
+// Тест требует БД PostgreSQL, строка подключения к которой задана в DS_DB_URL.
+// Данные теста удаляются после его завершения.
+func TestPgStore(t *testing.T) {
+	dbURL := os.Getenv("DS_DB_URL")
+	if dbURL == "" {
+		t.Skip("DS_DB_URL is not set")
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.Connect(ctx, dbURL)
+	require.NoError(t, err)
+	defer pool.Close()
+	_, err = migrations.Up(ctx, pool)
+	require.NoError(t, err)
+	defer cleanupPgStore(t, pool, t.Name()+"/%")
+
+	storetest.Run(t, entity.NewPgStore(pool))
+}
+
+// Удаляет данные каталогов, пути которых соответствуют шаблону `pattern`.
+func cleanupPgStore(t *testing.T, pool *pgxpool.Pool, pattern string) {
+	const ids = "SELECT id FROM audio.album_entry WHERE path LIKE $1"
+	for _, qry := range []string{
+		"DELETE FROM audio.event_outbox WHERE payload->>'path' LIKE $1",
+		"DELETE FROM audio.picture WHERE entity_type='album_entry' AND entity_id IN (" + ids + ")",
+		"DELETE FROM audio.actor WHERE entry_id IN (" + ids + ")",
+		"DELETE FROM audio.suggestion WHERE entry_id IN (" + ids + ")",
+		"DELETE FROM audio.bad_suggestion WHERE entry_id IN (" + ids + ")",
+		"DELETE FROM audio.accepted_suggestion WHERE entry_id IN (" + ids + ")",
+		"DELETE FROM audio.album_entry_revision WHERE entry_id IN (" + ids + ")",
+		"DELETE FROM audio.album_entry WHERE path LIKE $1",
+	} {
+		_, err := pool.Exec(context.Background(), qry, pattern)
+		assert.NoError(t, err, qry)
+	}
+}
@@ -0,0 +1,448 @@
+// Package storetest содержит общий набор тестов реализаций хранилища entity.Store.
+package storetest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+)
+
+// Возвращает путь к файлу тестовых данных модуля.
+func testdata(name string) string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "testdata", name)
+}
+
+// Выполняет `fn` в транзакции хранилища и фиксирует ее при отсутствии ошибки.
+func inTx(t *testing.T, s entity.Store, fn func(ctx context.Context) error) error {
+	ctx, tx, err := s.Begin(context.Background())
+	require.NoError(t, err)
+	if err = fn(ctx); err != nil {
+		require.NoError(t, tx.Rollback(ctx))
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// Коды ошибок PostgreSQL, соответствующие нарушениям ограничений хранилищ.
+var pgConstraintCodes = map[error][]string{
+	entity.ErrDuplicateKey:  {"23505"},
+	entity.ErrDuplicatePath: {"23505"},
+	entity.ErrForeignKey:    {"23503"},
+	entity.ErrBadValue:      {"23502", "23514", "22P02"},
+}
+
+// Проверяет, что ошибка соответствует нарушению ограничения `target`.
+// PgStore возвращает в этом случае ошибку PostgreSQL.
+func assertConstraint(t *testing.T, err, target error, msgAndArgs ...interface{}) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		assert.Contains(t, pgConstraintCodes[target], pgErr.Code, msgAndArgs...)
+		return
+	}
+	assert.ErrorIs(t, err, target, msgAndArgs...)
+}
+
+// Проверяет, что изменение данных вне транзакции отклонено.
+func assertTxRequired(t *testing.T, err error) {
+	assert.True(t, errors.Is(err, entity.ErrTxRequired) || errors.Is(err, entity.ErrConnectionInContext),
+		"unexpected error: %v", err)
+}
+
+// Run проверяет соответствие хранилища `s` контракту entity.Store. Данные тестов
+// размещаются в каталогах с префиксом пути, совпадающим с именем теста, что позволяет
+// выполнять проверку в БД с другими данными.
+func Run(t *testing.T, s entity.Store) {
+	t.Run("Entry", func(t *testing.T) { testStoreEntry(t, s, t.Name()+"/") })
+	t.Run("Rollback", func(t *testing.T) { testStoreRollback(t, s, t.Name()+"/") })
+	t.Run("References", func(t *testing.T) { testStoreReferences(t, s, t.Name()+"/") })
+	t.Run("BadValues", func(t *testing.T) { testStoreBadValues(t, s, t.Name()+"/") })
+	t.Run("List", func(t *testing.T) { testStoreList(t, s, t.Name()+"/") })
+	t.Run("Search", func(t *testing.T) { testStoreSearch(t, s, t.Name()+"/") })
+	t.Run("Events", func(t *testing.T) { testStoreEvents(t, s, t.Name()+"/") })
+}
+
+func testStoreEntry(t *testing.T, s entity.Store, prefix string) {
+	ctx := context.Background()
+	modified := time.Date(2021, 6, 4, 13, 55, 59, 0, time.UTC)
+	ent := &entity.AlbumEntry{
+		Path:         prefix + "a/b",
+		Json:         []byte(`{"title":"x"}`),
+		Status:       entity.StatusWithoutMandatoryTags,
+		LastModified: modified}
+
+	assertTxRequired(t, s.CreateEntry(ctx, ent))
+	require.NoError(t, inTx(t, s, func(ctx context.Context) error {
+		return s.CreateEntry(ctx, ent)
+	}))
+	assert.NotZero(t, ent.ID)
+	assert.Equal(t, 1, ent.Version)
+
+	err := inTx(t, s, func(ctx context.Context) error {
+		return s.CreateEntry(ctx, &entity.AlbumEntry{Path: prefix + "a/b", Status: entity.StatusFinalyzed})
+	})
+	assertConstraint(t, err, entity.ErrDuplicatePath)
+
+	got := &entity.AlbumEntry{Path: prefix + "a/b"}
+	require.NoError(t, s.GetEntry(ctx, got))
+	assert.Equal(t, ent.ID, got.ID)
+	assert.JSONEq(t, string(ent.Json), string(got.Json))
+	assert.True(t, modified.Equal(got.LastModified))
+
+	moved := &entity.AlbumEntry{ID: ent.ID, Path: prefix + "a/c", Status: entity.StatusFinalyzed, Version: 1}
+	require.NoError(t, inTx(t, s, func(ctx context.Context) error {
+		return s.UpdateEntry(ctx, moved)
+	}))
+	assert.Equal(t, 2, moved.Version)
+	moved.Version = 1
+	err = inTx(t, s, func(ctx context.Context) error {
+		return s.UpdateEntry(ctx, moved)
+	})
+	assert.ErrorIs(t, err, entity.ErrVersionConflict)
+
+	err = s.GetEntry(ctx, &entity.AlbumEntry{Path: prefix + "a/b"})
+	assert.ErrorIs(t, err, entity.ErrNotFound)
+
+	require.NoError(t, inTx(t, s, func(ctx context.Context) error {
+		return s.MarkEntriesStale(ctx, []int{ent.ID}, modified)
+	}))
+	entries, err := s.LibraryEntries(ctx)
+	require.NoError(t, err)
+	var stale *entity.AlbumEntry
+	for _, e := range entries {
+		if e.ID == ent.ID {
+			stale = e
+		}
+	}
+	require.NotNil(t, stale)
+	require.NotNil(t, stale.StaleSince)
+	assert.True(t, modified.Equal(*stale.StaleSince))
+}
+
+func testStoreRollback(t *testing.T, s entity.Store, prefix string) {
+	ent := &entity.AlbumEntry{Path: prefix + "a", Status: entity.StatusWithMandatoryTags}
+	require.NoError(t, inTx(t, s, func(ctx context.Context) error {
+		return s.CreateEntry(ctx, ent)
+	}))
+
+	txctx, tx, err := s.Begin(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, s.CreateActor(txctx, &entity.Actor{EntryID: ent.ID, Name: "x"}))
+	_, err = s.MoveTree(txctx, prefix+"a", prefix+"b")
+	require.NoError(t, err)
+	actors, err := s.EntryActors(txctx, ent.ID)
+	require.NoError(t, err)
+	assert.Len(t, actors, 1)
+	require.NoError(t, tx.Rollback(txctx))
+	assert.Error(t, tx.Commit(txctx))
+
+	ctx := context.Background()
+	actors, err = s.EntryActors(ctx, ent.ID)
+	require.NoError(t, err)
+	assert.Empty(t, actors)
+	require.NoError(t, s.GetEntry(ctx, &entity.AlbumEntry{Path: prefix + "a"}))
+}
+
+func testStoreReferences(t *testing.T, s entity.Store, prefix string) {
+	ctx := context.Background()
+	ent := &entity.AlbumEntry{Path: prefix + "a", Status: entity.StatusWithoutMandatoryTags}
+	actor := &entity.Actor{EntryID: -1, Name: "x", IDs: [][2]string{{"discogs", "1"}}, EntityMask: 1}
+	err := inTx(t, s, func(ctx context.Context) error {
+		return s.CreateActor(ctx, actor)
+	})
+	assertConstraint(t, err, entity.ErrForeignKey)
+
+	require.NoError(t, inTx(t, s, func(ctx context.Context) error {
+		if err := s.CreateEntry(ctx, ent); err != nil {
+			return err
+		}
+		actor.EntryID = ent.ID
+		if err := s.CreateActor(ctx, actor); err != nil {
+			return err
+		}
+		return s.SaveEntryRevision(ctx, ent.ID, "set_entry")
+	}))
+	actors, err := s.EntryActors(ctx, ent.ID)
+	require.NoError(t, err)
+	require.Len(t, actors, 1)
+	assert.Equal(t, actor.IDs, actors[0].IDs)
+	revisions, err := s.EntryRevisions(ctx, ent.ID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	assert.Nil(t, revisions[0].Actors)
+	rev := &entity.EntryRevision{ID: revisions[0].ID}
+	require.NoError(t, s.GetEntryRevision(ctx, rev))
+	require.Len(t, rev.Actors, 1)
+	assert.Equal(t, actor.IDs, rev.Actors[0].IDs)
+	assert.Nil(t, rev.Accepted)
+
+	err = inTx(t, s, func(ctx context.Context) error {
+		return s.CreateActor(ctx, actor)
+	})
+	assertConstraint(t, err, entity.ErrDuplicateKey)
+	err = inTx(t, s, func(ctx context.Context) error {
+		return s.DeleteEntry(ctx, ent)
+	})
+	assertConstraint(t, err, entity.ErrForeignKey)
+	err = inTx(t, s, func(ctx context.Context) error {
+		return s.SaveEntryRevision(ctx, -1, "set_entry")
+	})
+	assert.ErrorIs(t, err, entity.ErrNotFound)
+
+	require.NoError(t, inTx(t, s, func(ctx context.Context) error {
+		if err := s.DeleteEntryActors(ctx, ent.ID); err != nil {
+			return err
+		}
+		if err := s.DeleteEntryRevisions(ctx, ent.ID); err != nil {
+			return err
+		}
+		return s.DeleteEntry(ctx, ent)
+	}))
+}
+
+func testStoreBadValues(t *testing.T, s entity.Store, prefix string) {
+	ent := &entity.AlbumEntry{Path: prefix + "a", Status: entity.StatusWithMandatoryTags}
+	require.NoError(t, inTx(t, s, func(ctx context.Context) error {
+		return s.CreateEntry(ctx, ent)
+	}))
+	for _, tc := range []struct {
+		name string
+		fn   func(ctx context.Context) error
+	}{
+		{"entry status", func(ctx context.Context) error {
+			return s.CreateEntry(ctx, &entity.AlbumEntry{Path: prefix + "b", Status: "unknown"})
+		}},
+		{"updated entry status", func(ctx context.Context) error {
+			return s.UpdateEntry(ctx, &entity.AlbumEntry{ID: ent.ID, Path: prefix + "a"})
+		}},
+		{"suggestion ext_db", func(ctx context.Context) error {
+			return s.CreateSuggestion(ctx, &entity.Suggestion{EntryID: ent.ID, ExtDB: "x", ExtID: "1"})
+		}},
+		{"bad suggestion ext_db", func(ctx context.Context) error {
+			return s.CreateBadSuggestion(ctx, &entity.BadSuggestion{EntryID: ent.ID, ExtDB: "x", ExtID: "1"})
+		}},
+		{"accepted suggestion ext_db", func(ctx context.Context) error {
+			return s.SaveAcceptedSuggestion(ctx, &entity.AcceptedSuggestion{
+				EntryID: ent.ID, ExtDB: "x", ExtID: "1", MergePolicy: "overwrite"})
+		}},
+		{"picture entity_type", func(ctx context.Context) error {
+			return s.CreatePicture(ctx, &entity.Picture{EntType: "x", EntID: ent.ID, PictType: "cover_front"})
+		}},
+		{"picture pict_type", func(ctx context.Context) error {
+			return s.CreatePicture(ctx, &entity.Picture{EntType: "album_entry", EntID: ent.ID, PictType: "x"})
+		}},
+	} {
+		assertConstraint(t, inTx(t, s, tc.fn), entity.ErrBadValue, tc.name)
+	}
+}
+
+func testStoreList(t *testing.T, s entity.Store, prefix string) {
+	ctx := context.Background()
+	now := time.Date(2021, 6, 4, 13, 55, 59, 0, time.UTC)
+	ids := map[string]int{}
+	require.NoError(t, inTx(t, s, func(ctx context.Context) error {
+		for i, path := range []string{"b/2", "a/1", "b/1", "c", "b_x"} {
+			ent := &entity.AlbumEntry{
+				Path:         prefix + path,
+				Status:       entity.StatusWithoutMandatoryTags,
+				LastModified: now.Add(time.Duration(i) * time.Hour)}
+			if err := s.CreateEntry(ctx, ent); err != nil {
+				return err
+			}
+			ids[path] = ent.ID
+		}
+		return s.CreateSuggestion(ctx, &entity.Suggestion{EntryID: ids["c"], ExtDB: "discogs", ExtID: "1"})
+	}))
+
+	f := &entity.EntryFilter{PathPrefix: prefix + "b/", Limit: 1}
+	page, next, err := s.ListEntries(ctx, f)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, prefix+"b/1", page[0].Path)
+	f.Cursor = next
+	page, next, err = s.ListEntries(ctx, f)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, prefix+"b/2", page[0].Path)
+	assert.Empty(t, next)
+
+	f = &entity.EntryFilter{PathPrefix: prefix, SortBy: entity.SortByLastModified, Desc: true, Limit: 2}
+	page, next, err = s.ListEntries(ctx, f)
+	require.NoError(t, err)
+	assert.Equal(t, []int{ids["b_x"], ids["c"]}, entryIDs(page))
+	f.Cursor = next
+	page, _, err = s.ListEntries(ctx, f)
+	require.NoError(t, err)
+	assert.Equal(t, []int{ids["b/1"], ids["a/1"]}, entryIDs(page))
+
+	hasSuggestions := true
+	page, _, err = s.ListEntries(ctx, &entity.EntryFilter{PathPrefix: prefix, HasSuggestions: &hasSuggestions})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, prefix+"c", page[0].Path)
+
+	_, _, err = s.ListEntries(ctx, &entity.EntryFilter{SortBy: "size"})
+	assert.ErrorIs(t, err, entity.ErrBadSortField)
+
+	tree, err := s.TreeEntries(ctx, prefix+"b")
+	require.NoError(t, err)
+	assert.Len(t, tree, 2)
+	library, err := s.LibraryEntries(ctx)
+	require.NoError(t, err)
+	n := 0
+	for _, ent := range library {
+		if ent.ID == ids[pathKey(prefix, ent.Path)] {
+			n++
+		}
+	}
+	assert.Equal(t, len(ids), n)
+	paths, err := s.ExistingPaths(ctx, []string{prefix + "c", prefix + "d", prefix + "a/1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{prefix + "a/1", prefix + "c"}, paths)
+
+	var moved int64
+	require.NoError(t, inTx(t, s, func(ctx context.Context) (err error) {
+		moved, err = s.MoveTree(ctx, prefix+"b", prefix+"d/b")
+		return
+	}))
+	assert.EqualValues(t, 2, moved)
+	paths, err = s.ExistingPaths(ctx, []string{prefix + "d/b/1", prefix + "d/b/2", prefix + "b_x"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{prefix + "b_x", prefix + "d/b/1", prefix + "d/b/2"}, paths)
+}
+
+// Возвращает путь каталога без префикса `prefix` или пустую строку для других каталогов.
+func pathKey(prefix, path string) string {
+	if len(path) <= len(prefix) || path[:len(prefix)] != prefix {
+		return ""
+	}
+	return path[len(prefix):]
+}
+
+func entryIDs(entries []*entity.AlbumEntry) []int {
+	ret := make([]int, 0, len(entries))
+	for _, ent := range entries {
+		ret = append(ret, ent.ID)
+	}
+	return ret
+}
+
+func testStoreSearch(t *testing.T, s entity.Store, prefix string) {
+	data, err := ioutil.ReadFile(testdata("test_assumption.json"))
+	require.NoError(t, err)
+	var assumption struct {
+		Release json.RawMessage `json:"release"`
+	}
+	require.NoError(t, json.Unmarshal(data, &assumption))
+
+	ctx := context.Background()
+	ent := &entity.AlbumEntry{Path: prefix + "test", Json: assumption.Release, Status: entity.StatusWithMandatoryTags}
+	require.NoError(t, inTx(t, s, func(ctx context.Context) error {
+		empty := &entity.AlbumEntry{Path: prefix + "empty", Status: entity.StatusWithoutMandatoryTags}
+		if err := s.CreateEntry(ctx, empty); err != nil {
+			return err
+		}
+		return s.CreateEntry(ctx, ent)
+	}))
+	// результаты поиска, относящиеся к каталогу теста
+	found := func(search *entity.EntrySearch) []*entity.SearchResult {
+		res, err := s.SearchEntries(ctx, search)
+		require.NoError(t, err, fmt.Sprintf("%+v", search))
+		var ret []*entity.SearchResult
+		for _, r := range res {
+			if r.Entry.ID == ent.ID {
+				ret = append(ret, r)
+			}
+		}
+		return ret
+	}
+
+	res := found(&entity.EntrySearch{Query: "Remagine", Fields: []string{entity.SearchFieldTitle}, Catno: "TMSA-055"})
+	require.Len(t, res, 1)
+	assert.Equal(t, prefix+"test", res[0].Entry.Path)
+	assert.Contains(t, res[0].Headline, "<b>Remagine</b>")
+
+	assert.Empty(t, found(&entity.EntrySearch{Catno: "TMSA-056"}))
+	assert.Empty(t, found(&entity.EntrySearch{Query: "remagine -enter"}))
+	assert.Len(t, found(&entity.EntrySearch{Query: "nothing or enter"}), 1)
+	assert.Empty(t, found(&entity.EntrySearch{Query: "remagine", Fields: []string{entity.SearchFieldTrack}}))
+
+	// акторы, указанные только в треках
+	tracks := &entity.AlbumEntry{
+		Path: prefix + "tracks",
+		Json: []byte(`{"title":"Tracks","tracks":[{"title":"One",
+			"actors":{"Trackperformer":{}},"actor_roles":{"Trackrole":["vocals"]},
+			"record":{"actors":{"Recordperformer":{}}},
+			"composition":{"actors":{"Trackcomposer":{}}}}]}`),
+		Status: entity.StatusWithMandatoryTags}
+	require.NoError(t, inTx(t, s, func(ctx context.Context) error {
+		return s.CreateEntry(ctx, tracks)
+	}))
+	for _, name := range []string{"Trackperformer", "Trackrole", "Recordperformer", "Trackcomposer"} {
+		res, err := s.SearchEntries(ctx,
+			&entity.EntrySearch{Query: name, Fields: []string{entity.SearchFieldActor}})
+		require.NoError(t, err, name)
+		var ids []int
+		for _, r := range res {
+			ids = append(ids, r.Entry.ID)
+		}
+		assert.Contains(t, ids, tracks.ID, name)
+	}
+
+	_, err = s.SearchEntries(ctx, &entity.EntrySearch{})
+	assert.ErrorIs(t, err, entity.ErrEmptySearch)
+}
+
+func testStoreEvents(t *testing.T, s entity.Store, prefix string) {
+	ctx := context.Background()
+	ev := &entity.EntryEvent{Type: entity.EntryCreated, Path: prefix + "a", CreatedAt: time.Now().UTC()}
+	require.NoError(t, inTx(t, s, func(ctx context.Context) error {
+		ent := &entity.AlbumEntry{Path: prefix + "a", Status: entity.StatusWithoutMandatoryTags}
+		if err := s.CreateEntry(ctx, ent); err != nil {
+			return err
+		}
+		ev.EntryID = ent.ID
+		return s.CreateEntryEvent(ctx, ev)
+	}))
+	assert.NotZero(t, ev.ID)
+
+	_, err := s.PendingEntryEvents(ctx, 10)
+	assertTxRequired(t, err)
+	// события других тестов в той же БД не учитываются
+	pending := func(ctx context.Context) (*entity.EntryEvent, error) {
+		events, err := s.PendingEntryEvents(ctx, 1000)
+		for _, e := range events {
+			if e.ID == ev.ID {
+				return e, err
+			}
+		}
+		return nil, err
+	}
+	require.NoError(t, inTx(t, s, func(ctx context.Context) error {
+		got, err := pending(ctx)
+		if err != nil {
+			return err
+		}
+		require.NotNil(t, got)
+		assert.Equal(t, prefix+"a", got.Path)
+		assert.Equal(t, ev.EntryID, got.EntryID)
+		return s.MarkEntryEventPublished(ctx, got)
+	}))
+	require.NoError(t, inTx(t, s, func(ctx context.Context) error {
+		got, err := pending(ctx)
+		assert.Nil(t, got)
+		return err
+	}))
+}
@@ -36,7 +36,7 @@ func (r *Suggestion) Create(ctx context.Context) error {
 func (r *Suggestion) Delete(ctx context.Context) error {
 	err := Delete(
 		ctx,
-		"DELETE FROM audio.release WHERE entry_id=$1 AND ext_db=$2 AND ext_id=$3",
+		"DELETE FROM audio.suggestion WHERE entry_id=$1 AND ext_db=$2 AND ext_id=$3",
 		r.EntryID, r.ExtDB, r.ExtID)
 	if err != nil {
 		err = errors.Wrap(err, "Suggestion.Delete() failed")
@@ -46,9 +46,9 @@ func (r *Suggestion) Delete(ctx context.Context) error {
 
 // Get ищет объект по значению ключа записи.
 func (r *Suggestion) Get(ctx context.Context) error {
-	qry := `SELECT json,score FROM audio.release
+	qry := `SELECT json,score FROM audio.suggestion
 	WHERE entry_id=$1 AND ext_db=$2 AND ext_id=$3 LIMIT 1`
-	row, err := Get(ctx, qry, r, r.EntryID, r.ExtDB, r.ExtID)
+	row, err := Get(ctx, qry, r.EntryID, r.ExtDB, r.ExtID)
 	if err != nil {
 		return errors.Wrap(err, "Suggestion.Get() select failed")
 	}
@@ -61,9 +61,9 @@ func (r *Suggestion) Get(ctx context.Context) error {
 
 // EntrySuggestions возвращает список рекомендованных релизов для данного album_entry.
 func EntrySuggestions(ctx context.Context, entryID int) ([]*Suggestion, error) {
-	db := ctx.Value("db").(*pgx.Conn)
-	if db == nil {
-		return nil, errors.Wrap(ErrConnectionInContext, "EntrySuggestions() failed")
+	db, err := Conn(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "EntrySuggestions() failed")
 	}
 
 	rows, err := db.Query(ctx, "SELECT * FROM audio.suggestion WHERE entry_id=$1", entryID)
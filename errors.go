@@ -0,0 +1,147 @@
+package dbm
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgconn"
+	"github.com/pkg/errors"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+)
+
+// ErrorCode описывает машиночитаемый код ошибки выполнения команды.
+type ErrorCode string
+
+// Коды ошибок выполнения команд.
+const (
+	CodeNotFound         ErrorCode = "not_found"
+	CodeConflict         ErrorCode = "conflict"
+	CodeValidationFailed ErrorCode = "validation_failed"
+	CodeAlreadyFinalyzed ErrorCode = "already_finalyzed"
+	CodePathExists       ErrorCode = "path_exists"
+	CodeDBUnavailable    ErrorCode = "db_unavailable"
+	CodeBadRequest       ErrorCode = "bad_request"
+	CodeInternal         ErrorCode = "internal"
+)
+
+// Ошибки выполнения команд, соответствующие кодам ошибок.
+// Ошибки, возвращаемые `AudioDBResponse.Err()`, совместимы с ними через errors.Is().
+var (
+	ErrNotFound         = errors.New("not found")
+	ErrConflict         = errors.New("conflict")
+	ErrValidationFailed = errors.New("validation failed")
+	ErrAlreadyFinalyzed = errors.New("entry is already finalyzed")
+	ErrPathExists       = errors.New("path already exists")
+	ErrDBUnavailable    = errors.New("database is unavailable")
+	ErrBadRequest       = errors.New("bad request")
+	ErrInternal         = errors.New("internal error")
+)
+
+// Ошибки разбора запроса.
+var (
+	ErrEntryRequired  = errors.Wrap(ErrBadRequest, "entry is required")
+	ErrUnknownCommand = errors.Wrap(ErrBadRequest, "unknown command")
+)
+
+var codeErrors = map[ErrorCode]error{
+	CodeNotFound:         ErrNotFound,
+	CodeConflict:         ErrConflict,
+	CodeValidationFailed: ErrValidationFailed,
+	CodeAlreadyFinalyzed: ErrAlreadyFinalyzed,
+	CodePathExists:       ErrPathExists,
+	CodeDBUnavailable:    ErrDBUnavailable,
+	CodeBadRequest:       ErrBadRequest,
+	CodeInternal:         ErrInternal,
+}
+
+// Коды ошибок PostgreSQL.
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgNotNullViolation    = "23502"
+	pgCheckViolation      = "23514"
+	pgStringTooLong       = "22001"
+	pgInvalidTextRepr     = "22P02"
+	pgTooManyConnections  = "53300"
+	pgConnectionClass     = "08"
+	pgOperatorIntervClass = "57P"
+)
+
+// Ограничение уникальности пути каталога.
+const albumEntryPathConstraint = "album_entry_path_key"
+
+// errorCode сопоставляет ошибке выполнения команды код ошибки ответа.
+func errorCode(err error) ErrorCode {
+	for code, sentinel := range codeErrors {
+		if errors.Is(err, sentinel) {
+			return code
+		}
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == pgUniqueViolation && pgErr.ConstraintName == albumEntryPathConstraint:
+			return CodePathExists
+		case pgErr.Code == pgUniqueViolation:
+			return CodeConflict
+		case pgErr.Code == pgForeignKeyViolation, pgErr.Code == pgNotNullViolation,
+			pgErr.Code == pgCheckViolation, pgErr.Code == pgStringTooLong,
+			pgErr.Code == pgInvalidTextRepr:
+			return CodeValidationFailed
+		case pgErr.Code == pgTooManyConnections,
+			strings.HasPrefix(pgErr.Code, pgConnectionClass),
+			strings.HasPrefix(pgErr.Code, pgOperatorIntervClass):
+			return CodeDBUnavailable
+		}
+		return CodeInternal
+	}
+
+	var netErr net.Error
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.Is(err, entity.ErrNotFound):
+		return CodeNotFound
+	case errors.Is(err, entity.ErrDuplicatePath):
+		return CodePathExists
+	case errors.Is(err, entity.ErrVersionConflict), errors.Is(err, entity.ErrDuplicateKey):
+		return CodeConflict
+	case errors.Is(err, entity.ErrForeignKey), errors.Is(err, entity.ErrBadValue):
+		return CodeValidationFailed
+	case errors.Is(err, entity.ErrBadCursor), errors.Is(err, entity.ErrBadSortField),
+		errors.Is(err, entity.ErrEmptySearch), errors.Is(err, entity.ErrBadSearchField),
+		errors.Is(err, ErrBadMergePolicy), errors.Is(err, ErrRevisionMismatch),
+		errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return CodeBadRequest
+	case errors.Is(err, entity.ErrBadStatusTransition):
+		return CodeValidationFailed
+	case errors.As(err, &netErr), pgconn.Timeout(err),
+		errors.Is(err, context.DeadlineExceeded):
+		return CodeDBUnavailable
+	}
+	return CodeInternal
+}
+
+// ResponseError описывает ошибку выполнения команды, полученную в ответе сервиса.
+// Ошибка совместима через errors.Is() с ошибкой, соответствующей ее коду (ErrNotFound и т.д.).
+type ResponseError struct {
+	Code    ErrorCode
+	Message string
+	Context string
+}
+
+func (e *ResponseError) Error() string {
+	if e.Context == "" {
+		return e.Message
+	}
+	return e.Context + ": " + e.Message
+}
+
+// Unwrap возвращает ошибку, соответствующую коду ошибки ответа.
+func (e *ResponseError) Unwrap() error {
+	return codeErrors[e.Code]
+}
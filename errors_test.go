@@ -0,0 +1,49 @@
+package dbm
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+)
+
+func TestErrorCode(t *testing.T) {
+	for err, code := range map[error]ErrorCode{
+		errors.Wrap(entity.ErrNotFound, "Get()"):           CodeNotFound,
+		errors.Wrap(entity.ErrVersionConflict, "Update()"): CodeConflict,
+		ErrEntryRequired: CodeBadRequest,
+		errors.Wrap(ErrAlreadyFinalyzed, "entry_id=1"):                         CodeAlreadyFinalyzed,
+		&pgconn.PgError{Code: "23505", ConstraintName: "album_entry_path_key"}: CodePathExists,
+		&pgconn.PgError{Code: "23502"}:                                         CodeValidationFailed,
+		&pgconn.PgError{Code: "08006"}:                                         CodeDBUnavailable,
+		errors.Wrap(entity.ErrDuplicatePath, "path=a"):                         CodePathExists,
+		errors.Wrap(entity.ErrForeignKey, "entry_id=1"):                        CodeValidationFailed,
+		errors.Wrap(entity.ErrBadValue, "status"):                              CodeValidationFailed,
+		errors.New("unexpected"):                                               CodeInternal,
+	} {
+		assert.Equal(t, code, errorCode(err), err.Error())
+	}
+}
+
+func TestResponseErr(t *testing.T) {
+	var resp AudioDBResponse
+	require.NoError(t, json.Unmarshal(
+		[]byte(`{"error":{"error":"no rows in result set","context":"get_entry","code":"not_found"}}`),
+		&resp))
+	err := resp.Err()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, "get_entry: no rows in result set", err.Error())
+
+	var respErr *ResponseError
+	require.True(t, errors.As(err, &respErr))
+	assert.Equal(t, CodeNotFound, respErr.Code)
+
+	resp.Error = nil
+	assert.NoError(t, resp.Err())
+}
@@ -0,0 +1,229 @@
+package dbm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/streadway/amqp"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+	srv "github.com/ytsiuryn/ds-microservice"
+)
+
+// Параметры публикации событий.
+const (
+	eventBatchSize     = 100
+	eventRelayInterval = 5 * time.Second
+)
+
+// ErrEventNotConfirmed возвращается, если брокер не подтвердил прием события.
+var ErrEventNotConfirmed = errors.New("event publishing is not confirmed by broker")
+
+// WithEventExchange включает публикацию событий изменения Entry в fanout exchange `name`.
+// По умолчанию события не публикуются и не сохраняются.
+// События публикует только сервис, запущенный StartWithConnection(); без брокера сообщений
+// (Execute(), InProcTransport) события лишь сохраняются в audio.event_outbox и публикуются
+// экземпляром сервиса, подключенным к брокеру и к той же БД.
+func WithEventExchange(name string) Option {
+	return func(m *Dbm) {
+		m.eventExchange = name
+	}
+}
+
+// recordEvent сохраняет событие в таблице audio.event_outbox в рамках транзакции команды.
+// При отключенной публикации событий не делает ничего.
+func (m *Dbm) recordEvent(ctx context.Context, ev *entity.EntryEvent) error {
+	if m.eventExchange == "" {
+		return nil
+	}
+	ev.CreatedAt = time.Now().UTC()
+	if err := m.store.CreateEntryEvent(ctx, ev); err != nil {
+		return err
+	}
+	if tx, ok := ctx.Value(cmdTxKey{}).(*cmdTx); ok {
+		tx.events = true
+	}
+	return nil
+}
+
+// Транзакция хранилища, начатая сервисом. После фиксации транзакции, в которой были
+// сохранены события, публикация событий запускается без ожидания периодической проверки.
+type cmdTx struct {
+	entity.StoreTx
+	events bool
+}
+
+type cmdTxKey struct{}
+
+// Начинает транзакцию хранилища (см. completeTx()).
+func (m *Dbm) begin(ctx context.Context) (context.Context, entity.StoreTx, error) {
+	txctx, tx, err := m.store.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	cmd := &cmdTx{StoreTx: tx}
+	return context.WithValue(txctx, cmdTxKey{}, cmd), cmd, nil
+}
+
+// notifyEvents сигнализирует о появлении новых событий для публикации.
+// Вызывается после фиксации транзакции, в которой были сохранены события.
+func (m *Dbm) notifyEvents() {
+	select {
+	case m.eventNotify <- struct{}{}:
+	default:
+	}
+}
+
+// Запускает публикацию сохраненных событий.
+// События публикуются по сигналу notifyEvents() и периодически - для событий, оставшихся
+// неопубликованными после сбоев.
+func (m *Dbm) startEventRelay() {
+	ch, err := m.amqpConn.Channel()
+	srv.FailOnError(err, "Failed to open an event channel")
+	err = ch.ExchangeDeclare(
+		m.eventExchange, // name
+		"fanout",        // type
+		true,            // durable
+		false,           // auto-deleted
+		false,           // internal
+		false,           // no-wait
+		nil,             // arguments
+	)
+	srv.FailOnError(err, "Failed to declare an event exchange")
+	srv.FailOnError(ch.Confirm(false), "Failed to put event channel into confirm mode")
+	confirms := ch.NotifyPublish(make(chan amqp.Confirmation, 1))
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.stopEventRelay = cancel
+	m.eventRelayDone = make(chan struct{})
+
+	go func() {
+		defer close(m.eventRelayDone)
+		defer ch.Close()
+		ticker := time.NewTicker(eventRelayInterval)
+		defer ticker.Stop()
+		for {
+			for {
+				n, err := m.relayEvents(ctx, ch, confirms)
+				if err != nil {
+					m.LogOnErrorWithContext(err, "Event relay")
+				}
+				if err != nil || n < eventBatchSize {
+					break
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-m.eventNotify:
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Канал брокера сообщений, в который публикуются события (реализуется *amqp.Channel).
+type eventPublisher interface {
+	Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+}
+
+// Публикует очередную порцию событий и возвращает число опубликованных событий.
+// События публикуются вне транзакции хранилища, чтобы ожидание подтверждения брокера
+// не блокировало команды, и помечаются опубликованными по одному после подтверждения.
+// Доставка выполняется "хотя бы один раз": событие, подтвержденное брокером, но не
+// помеченное из-за сбоя, а также событие, выбранное одновременно несколькими экземплярами
+// сервиса, может быть опубликовано повторно. Получатели должны учитывать поле `id` события.
+func (m *Dbm) relayEvents(
+	ctx context.Context, ch eventPublisher, confirms <-chan amqp.Confirmation) (n int, err error) {
+
+	events, err := m.pendingEvents(ctx)
+	if err != nil {
+		return
+	}
+	for _, ev := range events {
+		var data []byte
+		if data, err = json.Marshal(ev); err != nil {
+			return
+		}
+		err = ch.Publish(
+			m.eventExchange,
+			"",
+			false,
+			false,
+			amqp.Publishing{
+				ContentType:  "application/json",
+				DeliveryMode: amqp.Persistent,
+				Type:         ev.Type,
+				Body:         data,
+			})
+		if err != nil {
+			return
+		}
+		select {
+		case confirm, ok := <-confirms:
+			if !ok || !confirm.Ack {
+				return n, ErrEventNotConfirmed
+			}
+		case <-ctx.Done():
+			return n, ctx.Err()
+		}
+		if err = m.markEventPublished(ctx, ev); err != nil {
+			return
+		}
+		n++
+	}
+	return
+}
+
+// Возвращает порцию неопубликованных событий.
+func (m *Dbm) pendingEvents(ctx context.Context) (_ []*entity.EntryEvent, err error) {
+	txctx, tx, err := m.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer m.completeTx(tx, &err)
+	return m.store.PendingEntryEvents(txctx, eventBatchSize)
+}
+
+// Помечает событие опубликованным в отдельной транзакции.
+func (m *Dbm) markEventPublished(ctx context.Context, ev *entity.EntryEvent) (err error) {
+	txctx, tx, err := m.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer m.completeTx(tx, &err)
+	return m.store.MarkEntryEventPublished(txctx, ev)
+}
+
+// entryChanges возвращает наименования измененных полей Entry.
+func entryChanges(old, cur *entity.AlbumEntry) (ret []string) {
+	if old.Path != cur.Path {
+		ret = append(ret, "path")
+	}
+	if !equalJSON(old.Json, cur.Json) {
+		ret = append(ret, "json")
+	}
+	if old.Status != cur.Status {
+		ret = append(ret, "status")
+	}
+	if !old.LastModified.Equal(cur.LastModified) {
+		ret = append(ret, "last_modified")
+	}
+	return
+}
+
+// Сравнивает JSON-документы без учета форматирования и порядка ключей.
+func equalJSON(a, b []byte) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
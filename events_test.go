@@ -0,0 +1,147 @@
+package dbm
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+)
+
+// Канал брокера, подтверждающий публикации по заданному сценарию.
+type fakeEventChannel struct {
+	confirms chan amqp.Confirmation
+	nacks    map[int]bool // номера публикаций, не подтверждаемых брокером
+	onPub    func()
+	msgs     []amqp.Publishing
+}
+
+func newFakeEventChannel() *fakeEventChannel {
+	return &fakeEventChannel{confirms: make(chan amqp.Confirmation, 1), nacks: map[int]bool{}}
+}
+
+func (ch *fakeEventChannel) Publish(
+	exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+
+	if ch.onPub != nil {
+		ch.onPub()
+	}
+	ch.msgs = append(ch.msgs, msg)
+	n := len(ch.msgs)
+	ch.confirms <- amqp.Confirmation{DeliveryTag: uint64(n), Ack: !ch.nacks[n]}
+	return nil
+}
+
+func TestRelayEvents(t *testing.T) {
+	m := New("", WithStore(entity.NewMemStore()), WithEventExchange("events"))
+	defer m.Close()
+	ctx := context.Background()
+	for _, path := range []string{"a", "b", "c"} {
+		_, err := m.Execute(NewAudioDBRequest("set_entry", &entity.AlbumEntry{Path: path}))
+		require.NoError(t, err)
+	}
+
+	// публикация не удерживает транзакцию хранилища
+	ch := newFakeEventChannel()
+	ch.nacks[2] = true
+	ch.onPub = func() {
+		done := make(chan error, 1)
+		go func() {
+			_, err := m.Execute(NewAudioDBRequest("get_entry", &entity.AlbumEntry{Path: "a"}))
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Error("store is locked while publishing")
+		}
+	}
+	n, err := m.relayEvents(ctx, ch, ch.confirms)
+	assert.ErrorIs(t, err, ErrEventNotConfirmed)
+	assert.Equal(t, 1, n)
+	var ev entity.EntryEvent
+	require.NoError(t, json.Unmarshal(ch.msgs[0].Body, &ev))
+	assert.Equal(t, "entry_created", ev.Type)
+	assert.Equal(t, "a", ev.Path)
+
+	// подтвержденное событие не публикуется повторно после сбоя
+	ch = newFakeEventChannel()
+	n, err = m.relayEvents(ctx, ch, ch.confirms)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	require.NoError(t, json.Unmarshal(ch.msgs[0].Body, &ev))
+	assert.Equal(t, "b", ev.Path)
+
+	n, err = m.relayEvents(ctx, ch, ch.confirms)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	// ожидание подтверждения ограничено контекстом
+	_, err = m.Execute(NewAudioDBRequest("set_entry", &entity.AlbumEntry{Path: "d"}))
+	require.NoError(t, err)
+	cctx, cancel := context.WithCancel(ctx)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	n, err = m.relayEvents(cctx, silentChannel{}, make(chan amqp.Confirmation))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, n)
+}
+
+// Канал брокера, не отправляющий подтверждений.
+type silentChannel struct{}
+
+func (silentChannel) Publish(string, string, bool, bool, amqp.Publishing) error { return nil }
+
+func TestNotifyEvents(t *testing.T) {
+	m := New("", WithStore(entity.NewMemStore()), WithEventExchange("events"))
+	defer m.Close()
+	notified := func() bool {
+		select {
+		case <-m.eventNotify:
+			return true
+		default:
+			return false
+		}
+	}
+
+	executeCmd(t, m, NewAudioDBRequest("set_entry", &entity.AlbumEntry{Path: "a"}))
+	assert.True(t, notified())
+	// команды, не сохранившие событий, публикацию не запускают
+	executeCmd(t, m, NewAudioDBRequest("get_entry", &entity.AlbumEntry{Path: "a"}))
+	executeCmd(t, m, NewAudioDBRequest("list_entries", nil))
+	_, err := m.Execute(NewAudioDBRequest("rename_entry", &entity.AlbumEntry{Path: "a", Version: 5}))
+	assert.ErrorIs(t, err, entity.ErrVersionConflict)
+	assert.False(t, notified())
+}
+
+func TestEntryChanges(t *testing.T) {
+	ts := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	old := &entity.AlbumEntry{
+		Path: "a", Json: []byte(`{"x":1,"y":[1,2]}`), Status: "new", LastModified: ts}
+
+	cur := *old
+	cur.Json = []byte(`{ "y": [1, 2], "x": 1 }`)
+	cur.LastModified = ts.In(time.FixedZone("MSK", 3*3600))
+	assert.Empty(t, entryChanges(old, &cur))
+
+	cur = entity.AlbumEntry{
+		Path: "b", Json: []byte(`{"x":2}`), Status: "finalyzed", LastModified: ts.Add(time.Second)}
+	assert.Equal(t, []string{"path", "json", "status", "last_modified"}, entryChanges(old, &cur))
+}
+
+func TestEqualJSON(t *testing.T) {
+	assert.True(t, equalJSON(nil, nil))
+	assert.True(t, equalJSON([]byte(`{"a":1,"b":null}`), []byte(`{"b":null, "a":1}`)))
+	assert.False(t, equalJSON([]byte(`{"a":1}`), []byte(`{"a":"1"}`)))
+	assert.False(t, equalJSON([]byte(`[1,2]`), []byte(`[2,1]`)))
+	assert.False(t, equalJSON([]byte(`{"a":`), []byte(`{"a":`+"\t")))
+	assert.False(t, equalJSON(nil, []byte(`{}`)))
+}
@@ -0,0 +1,197 @@
+package dbm
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+	"github.com/ytsiuryn/ds-audiodbm/entity/sqlitestore"
+	md "github.com/ytsiuryn/ds-audiomd"
+)
+
+func TestExecuteWithMemStore(t *testing.T) {
+	testExecute(t, New("", WithStore(entity.NewMemStore())))
+}
+
+func TestExecuteWithSQLiteStore(t *testing.T) {
+	m := New(sqlitestore.URLScheme+filepath.Join(t.TempDir(), "test.db"), WithMigrate())
+	defer m.Close()
+	testExecute(t, m)
+}
+
+// Выполняет команды сервиса с хранилищем, отличным от PostgreSQL.
+func testExecute(t *testing.T, m *Dbm) {
+	data, err := ioutil.ReadFile("testdata/test_assumption.json")
+	require.NoError(t, err)
+	testAssumption := md.NewAssumption(nil)
+	require.NoError(t, json.Unmarshal(data, &testAssumption))
+
+	req := NewAudioDBRequest("set_entry", &entity.AlbumEntry{Path: "test"})
+	require.NoError(t, req.ImportAssumption(testAssumption))
+	answ := executeCmd(t, m, req)
+	require.NotZero(t, answ.Entry.ID)
+	assert.Equal(t, entity.StatusWithMandatoryTags, answ.Entry.Status)
+	req.Entry.ID = answ.Entry.ID
+
+	req.Cmd = "get_entry"
+	req.ClearMetaData()
+	answ = executeCmd(t, m, req)
+	assert.Equal(t, "test", answ.Entry.Path)
+	assert.Len(t, answ.Actors, 1)
+	assert.Len(t, answ.Pictures, 1)
+	version := answ.Entry.Version
+
+	searchReq := NewAudioDBRequest("search_entries", nil)
+	searchReq.Search = &entity.EntrySearch{Query: "Remagine", Catno: "TMSA-055"}
+	answ = executeCmd(t, m, searchReq)
+	require.Len(t, answ.SearchResults, 1)
+	assert.Equal(t, "test", answ.SearchResults[0].Entry.Path)
+
+	other := NewAudioDBRequest("set_entry", &entity.AlbumEntry{Path: "other"})
+	executeCmd(t, m, other)
+
+	renameReq := NewAudioDBRequest("rename_entry", &entity.AlbumEntry{ID: req.Entry.ID})
+	renameReq.NewPath = "other"
+	_, err = m.Execute(renameReq)
+	assert.Equal(t, CodePathExists, errorCode(err))
+
+	req.Cmd = "set_entry"
+	require.NoError(t, req.ImportAssumption(testAssumption))
+	req.Entry.Version = version + 1
+	_, err = m.Execute(req)
+	assert.ErrorIs(t, err, entity.ErrVersionConflict)
+
+	// Неудачные команды не изменяют данных.
+	req.Cmd = "get_entry"
+	req.ClearMetaData()
+	answ = executeCmd(t, m, req)
+	assert.Equal(t, "test", answ.Entry.Path)
+	assert.Equal(t, version, answ.Entry.Version)
+
+	moveReq := NewAudioDBRequest("move_tree", nil)
+	moveReq.OldPrefix, moveReq.NewPrefix = "test", "moved/test"
+	answ = executeCmd(t, m, moveReq)
+	require.Len(t, answ.Moves, 1)
+	assert.Equal(t, "moved/test", answ.Moves[0].NewPath)
+
+	req.Cmd = "finalyze_entry"
+	req.Entry.Path, req.Entry.Version = "", 0
+	answ = executeCmd(t, m, req)
+	assert.Equal(t, entity.StatusFinalyzed, answ.Entry.Status)
+
+	req.Cmd = "set_entry"
+	req.Entry.Version = 0
+	require.NoError(t, req.ImportAssumption(testAssumption))
+	_, err = m.Execute(req)
+	assert.ErrorIs(t, err, ErrAlreadyFinalyzed)
+
+	moveReq.OldPrefix, moveReq.NewPrefix = "moved/test", "test"
+	_, err = m.Execute(moveReq)
+	assert.ErrorIs(t, err, ErrAlreadyFinalyzed)
+
+	historyReq := NewAudioDBRequest("get_entry_history", &entity.AlbumEntry{ID: req.Entry.ID})
+	answ = executeCmd(t, m, historyReq)
+	assert.NotEmpty(t, answ.Revisions)
+
+	req.Cmd = "delete_entry"
+	req.ClearMetaData()
+	executeCmd(t, m, req)
+	_, err = m.Execute(NewAudioDBRequest("get_entry", &entity.AlbumEntry{ID: req.Entry.ID}))
+	assert.Equal(t, CodeNotFound, errorCode(err))
+}
+
+func TestVersionConflict(t *testing.T) {
+	store := entity.NewMemStore()
+	m := New("", WithStore(store))
+	defer m.Close()
+	entry := executeCmd(t, m, NewAudioDBRequest("set_entry", &entity.AlbumEntry{Path: "a"})).Entry
+	stale := entry.Version
+	executeCmd(t, m, NewAudioDBRequest("set_entry", &entity.AlbumEntry{ID: entry.ID, Path: "a"}))
+	ctx := context.Background()
+	txctx, tx, err := store.Begin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, store.CreateSuggestion(txctx, &entity.Suggestion{
+		EntryID: entry.ID, ExtDB: "discogs", ExtID: "1", Json: []byte(`{"title":"b"}`)}))
+	require.NoError(t, tx.Commit(ctx))
+	history := executeCmd(t, m, NewAudioDBRequest("get_entry_history", &entity.AlbumEntry{ID: entry.ID}))
+	require.Len(t, history.Revisions, 1)
+
+	// устаревшая версия отклоняется без изменения данных
+	renameReq := NewAudioDBRequest("rename_entry", &entity.AlbumEntry{ID: entry.ID, Version: stale})
+	renameReq.NewPath = "b"
+	acceptReq := NewAudioDBRequest("accept_suggestion", &entity.AlbumEntry{ID: entry.ID, Version: stale})
+	acceptReq.ExtDB, acceptReq.ExtID = "discogs", "1"
+	revertReq := NewAudioDBRequest("revert_entry", &entity.AlbumEntry{ID: entry.ID, Version: stale})
+	revertReq.RevisionID = history.Revisions[0].ID
+	for _, req := range []*AudioDBRequest{
+		renameReq,
+		acceptReq,
+		revertReq,
+		NewAudioDBRequest("finalyze_entry", &entity.AlbumEntry{ID: entry.ID, Version: stale}),
+	} {
+		data, err := m.Execute(req)
+		assert.ErrorIs(t, err, entity.ErrVersionConflict, req.Cmd)
+		assert.Nil(t, data)
+	}
+	answ := executeCmd(t, m, NewAudioDBRequest("get_entry", &entity.AlbumEntry{ID: entry.ID}))
+	assert.Equal(t, "a", answ.Entry.Path)
+	assert.Equal(t, stale+1, answ.Entry.Version)
+	assert.NotEqual(t, entity.StatusFinalyzed, answ.Entry.Status)
+	history = executeCmd(t, m, NewAudioDBRequest("get_entry_history", &entity.AlbumEntry{ID: entry.ID}))
+	assert.Len(t, history.Revisions, 1)
+	err = store.GetAcceptedSuggestion(ctx, &entity.AcceptedSuggestion{EntryID: entry.ID})
+	assert.ErrorIs(t, err, entity.ErrNotFound)
+
+	// нулевая версия отключает проверку
+	renameReq = NewAudioDBRequest("rename_entry", &entity.AlbumEntry{Path: "a"})
+	renameReq.NewPath = "b"
+	answ = executeCmd(t, m, renameReq)
+	assert.Equal(t, stale+2, answ.Entry.Version)
+	answ = executeCmd(t, m, NewAudioDBRequest("finalyze_entry", &entity.AlbumEntry{Path: "b"}))
+	assert.Equal(t, entity.StatusFinalyzed, answ.Entry.Status)
+
+	_, err = m.Execute(
+		NewAudioDBRequest("unfinalyze_entry", &entity.AlbumEntry{ID: entry.ID, Version: stale}))
+	assert.ErrorIs(t, err, entity.ErrVersionConflict)
+	answ = executeCmd(t, m,
+		NewAudioDBRequest("unfinalyze_entry", &entity.AlbumEntry{ID: entry.ID, Version: stale + 3}))
+	assert.NotEqual(t, entity.StatusFinalyzed, answ.Entry.Status)
+}
+
+func TestFinalyzeEntryByPath(t *testing.T) {
+	m := New("", WithStore(entity.NewMemStore()))
+	defer m.Close()
+	assumption := readTestAssumption(t)
+	set := md.NewSuggestionSet()
+	suggestion := md.NewSuggestion()
+	suggestion.Release = assumption.Release
+	suggestion.Release.IDs["discogs"] = "1234"
+	suggestion.ServiceName = "discogs"
+	set.Suggestions = append(set.Suggestions, suggestion)
+	req := NewAudioDBRequest("set_entry", &entity.AlbumEntry{Path: "a"})
+	require.NoError(t, req.ImportAssumption(assumption))
+	require.NoError(t, req.ImportSuggestions(set))
+	id := executeCmd(t, m, req).Entry.ID
+	require.Len(t, executeCmd(t, m,
+		NewAudioDBRequest("get_entry", &entity.AlbumEntry{ID: id})).Suggestions, 1)
+
+	// предложения удаляются у каталога, заданного путем
+	executeCmd(t, m, NewAudioDBRequest("finalyze_entry", &entity.AlbumEntry{Path: "a"}))
+	answ := executeCmd(t, m, NewAudioDBRequest("get_entry", &entity.AlbumEntry{ID: id}))
+	assert.Equal(t, entity.StatusFinalyzed, answ.Entry.Status)
+	assert.Empty(t, answ.Suggestions)
+}
+
+func executeCmd(t *testing.T, m *Dbm, req *AudioDBRequest) *AudioDBRequest {
+	data, err := m.Execute(req)
+	require.NoError(t, err, req.Cmd)
+	var answ AudioDBRequest
+	require.NoError(t, json.Unmarshal(data, &answ))
+	return &answ
+}
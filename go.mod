@@ -3,9 +3,11 @@ module github.com/ytsiuryn/ds-audiodbm
 go 1.16
 
 require (
+	github.com/fsnotify/fsnotify v1.5.1
 	github.com/gofrs/uuid v4.0.0+incompatible
+	github.com/jackc/pgconn v1.10.0
 	github.com/jackc/pgx/v4 v4.13.0
-	github.com/jmoiron/sqlx v1.3.4
+	github.com/mattn/go-sqlite3 v1.14.16
 	github.com/pkg/errors v0.9.1
 	github.com/sirupsen/logrus v1.8.1
 	github.com/streadway/amqp v1.0.0
@@ -13,4 +15,6 @@ require (
 	github.com/ytsiuryn/ds-audiomd v0.3.0
 	github.com/ytsiuryn/ds-microservice v0.8.2
 	github.com/ytsiuryn/go-collection v0.0.2
+	google.golang.org/grpc v1.46.2
+	google.golang.org/protobuf v1.28.1
 )
@@ -0,0 +1,310 @@
+package dbm
+
+import (
+	"context"
+	"net"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	pb "github.com/ytsiuryn/ds-audiodbm/audiodbpb"
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+)
+
+const grpcContext = "gRPC server"
+
+// Соответствие кодов ошибок выполнения команд кодам статуса gRPC.
+var grpcCodes = map[ErrorCode]codes.Code{
+	CodeNotFound:         codes.NotFound,
+	CodeConflict:         codes.Aborted,
+	CodeValidationFailed: codes.FailedPrecondition,
+	CodeAlreadyFinalyzed: codes.FailedPrecondition,
+	CodePathExists:       codes.AlreadyExists,
+	CodeDBUnavailable:    codes.Unavailable,
+	CodeBadRequest:       codes.InvalidArgument,
+	CodeInternal:         codes.Internal,
+}
+
+// ErrBadReleaseJSON возвращается, если метаданные релиза не являются корректным JSON.
+var ErrBadReleaseJSON = errors.Wrap(ErrBadRequest, "release metadata is not a valid JSON")
+
+// WithGRPC включает сервер gRPC (контракт audiodbpb.AudioDB) по адресу `addr`
+// наряду с обработкой запросов от брокера сообщений.
+func WithGRPC(addr string) Option {
+	return func(m *Dbm) {
+		m.grpcAddr = addr
+	}
+}
+
+// grpcService реализует сервис gRPC с помощью тех же команд, что выполняются для запросов
+// от брокера сообщений, и тем же диспетчером сервиса. Ошибки выполнения команд возвращаются
+// статусом gRPC с деталями audiodbpb.ErrorDetail.
+type grpcService struct {
+	pb.UnimplementedAudioDBServer
+	m *Dbm
+}
+
+// RegisterGRPC регистрирует сервис каталога на сервере gRPC приложения.
+func (m *Dbm) RegisterGRPC(s grpc.ServiceRegistrar) {
+	pb.RegisterAudioDBServer(s, &grpcService{m: m})
+}
+
+// Запускает сервер gRPC.
+func (m *Dbm) startGRPC() {
+	lis, err := net.Listen("tcp", m.grpcAddr)
+	if err != nil {
+		m.Log.Fatalln(err)
+	}
+	m.grpcServer = grpc.NewServer()
+	m.RegisterGRPC(m.grpcServer)
+	m.grpcDone = make(chan struct{})
+
+	go func() {
+		defer close(m.grpcDone)
+		m.Log.Infof("Serving gRPC requests on %s", lis.Addr())
+		m.LogOnErrorWithContext(m.grpcServer.Serve(lis), grpcContext)
+	}()
+}
+
+// Останавливает сервер gRPC, ожидая завершения выполняемых запросов.
+func (m *Dbm) stopGRPC() {
+	m.grpcServer.GracefulStop()
+	<-m.grpcDone
+}
+
+// Выполняет команду в порядке поступления команд для того же Entry.
+// Ожидание выполнения прекращается при отмене вызова или истечении его срока действия;
+// команда, не начавшая выполняться к этому моменту, не выполняется.
+func (s *grpcService) execute(ctx context.Context, req *AudioDBRequest) error {
+	var cmdErr error
+	err := s.m.disp.DoContext(ctx, s.m.entryKey(req), func() {
+		s.m.logRequest(req)
+		_, cmdErr = s.m.Execute(req)
+	})
+	if err != nil {
+		return status.FromContextError(err).Err()
+	}
+	if cmdErr != nil {
+		return s.grpcError(req, cmdErr)
+	}
+	return nil
+}
+
+// Формирует статус gRPC для ошибки выполнения команды.
+func (s *grpcService) grpcError(req *AudioDBRequest, err error) error {
+	s.m.LogOnErrorWithContext(err, req.Cmd)
+	code := errorCode(err)
+	detail := &pb.ErrorDetail{Code: string(code), Context: req.Cmd}
+	switch {
+	case errors.Is(err, entity.ErrVersionConflict):
+		detail.State = entryDataToPB(s.m.conflictState(req))
+	case errors.Is(err, ErrPathExists) && len(req.Collisions) > 0:
+		detail.Moves = movesToPB(req)
+	}
+	st, detailErr := status.New(grpcCodes[code], err.Error()).WithDetails(detail)
+	if detailErr != nil {
+		return status.Error(grpcCodes[code], err.Error())
+	}
+	return st.Err()
+}
+
+func (s *grpcService) Ping(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+func (s *grpcService) GetEntry(ctx context.Context, ref *pb.EntryRef) (*pb.EntryData, error) {
+	req := NewAudioDBRequest("get_entry", refFromPB(ref))
+	if err := s.execute(ctx, req); err != nil {
+		return nil, err
+	}
+	return entryDataToPB(req), nil
+}
+
+func (s *grpcService) SetEntry(ctx context.Context, data *pb.EntryData) (*pb.EntryData, error) {
+	req := requestFromEntryData("set_entry", data)
+	if req.Entry != nil && !validJSON(req.Entry.Json) {
+		return nil, s.grpcError(req, ErrBadReleaseJSON)
+	}
+	if err := s.execute(ctx, req); err != nil {
+		return nil, err
+	}
+	return entryDataToPB(req), nil
+}
+
+func (s *grpcService) DeleteEntry(ctx context.Context, ref *pb.EntryRef) (*emptypb.Empty, error) {
+	if err := s.execute(ctx, NewAudioDBRequest("delete_entry", refFromPB(ref))); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *grpcService) FinalyzeEntry(ctx context.Context, ref *pb.EntryRef) (*pb.EntryData, error) {
+	req := NewAudioDBRequest("finalyze_entry", refFromPB(ref))
+	if err := s.execute(ctx, req); err != nil {
+		return nil, err
+	}
+	return entryDataToPB(req), nil
+}
+
+func (s *grpcService) UnfinalyzeEntry(ctx context.Context, ref *pb.EntryRef) (*pb.EntryData, error) {
+	req := NewAudioDBRequest("unfinalyze_entry", refFromPB(ref))
+	if err := s.execute(ctx, req); err != nil {
+		return nil, err
+	}
+	return entryDataToPB(req), nil
+}
+
+func (s *grpcService) RenameEntry(
+	ctx context.Context, r *pb.RenameEntryRequest) (*pb.AlbumEntry, error) {
+
+	req := NewAudioDBRequest("rename_entry", refFromPB(r.Entry))
+	req.NewPath = r.NewPath
+	if err := s.execute(ctx, req); err != nil {
+		return nil, err
+	}
+	req.Entry.Path = req.NewPath
+	return entryToPB(req.Entry), nil
+}
+
+func (s *grpcService) MoveTree(
+	ctx context.Context, r *pb.MoveTreeRequest) (*pb.MoveTreeResponse, error) {
+
+	req := NewAudioDBRequest("move_tree", nil)
+	req.OldPrefix, req.NewPrefix, req.DryRun = r.OldPrefix, r.NewPrefix, r.DryRun
+	if err := s.execute(ctx, req); err != nil {
+		return nil, err
+	}
+	return movesToPB(req), nil
+}
+
+func (s *grpcService) Reconcile(
+	ctx context.Context, r *pb.ReconcileRequest) (*pb.ReconcileReport, error) {
+
+	req := NewAudioDBRequest("reconcile", nil)
+	req.OrphanAction = r.OrphanAction
+	if err := s.execute(ctx, req); err != nil {
+		return nil, err
+	}
+	return reconcileReportToPB(req.Reconcile), nil
+}
+
+// Выбирает каталоги, соответствующие фильтру, постранично и передает их `fn`.
+func (s *grpcService) listEntries(
+	ctx context.Context, f *pb.EntryFilter, fn func(*entity.AlbumEntry) error) error {
+
+	filter := filterFromPB(f)
+	for {
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		req := NewAudioDBRequest("list_entries", nil)
+		req.Filter = filter
+		if err := s.execute(ctx, req); err != nil {
+			return err
+		}
+		for _, ent := range req.Entries {
+			if err := ctx.Err(); err != nil {
+				return status.FromContextError(err).Err()
+			}
+			if err := fn(ent); err != nil {
+				return err
+			}
+		}
+		if req.NextCursor == "" {
+			return nil
+		}
+		filter.Cursor = req.NextCursor
+	}
+}
+
+func (s *grpcService) ListEntries(f *pb.EntryFilter, stream pb.AudioDB_ListEntriesServer) error {
+	return s.listEntries(stream.Context(), f, func(ent *entity.AlbumEntry) error {
+		return stream.Send(entryToPB(ent))
+	})
+}
+
+func (s *grpcService) ExportEntries(f *pb.EntryFilter, stream pb.AudioDB_ExportEntriesServer) error {
+	ctx := stream.Context()
+	return s.listEntries(ctx, f, func(ent *entity.AlbumEntry) error {
+		req := NewAudioDBRequest("get_entry", &entity.AlbumEntry{ID: ent.ID})
+		err := s.execute(ctx, req)
+		if status.Code(err) == codes.NotFound {
+			// каталог удален после выборки страницы
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return stream.Send(entryDataToPB(req))
+	})
+}
+
+func (s *grpcService) SearchEntries(
+	ctx context.Context, r *pb.SearchRequest) (*pb.SearchResponse, error) {
+
+	req := NewAudioDBRequest("search_entries", nil)
+	req.Search = searchFromPB(r)
+	if err := s.execute(ctx, req); err != nil {
+		return nil, err
+	}
+	return searchResultsToPB(req.SearchResults), nil
+}
+
+func (s *grpcService) AcceptSuggestion(
+	ctx context.Context, r *pb.SuggestionRequest) (*pb.EntryData, error) {
+
+	req := NewAudioDBRequest("accept_suggestion", refFromPB(r.Entry))
+	req.ExtDB, req.ExtID, req.MergePolicy = r.ExtDb, r.ExtId, r.MergePolicy
+	if err := s.execute(ctx, req); err != nil {
+		return nil, err
+	}
+	return entryDataToPB(req), nil
+}
+
+func (s *grpcService) RejectSuggestion(
+	ctx context.Context, r *pb.SuggestionRequest) (*emptypb.Empty, error) {
+
+	req := NewAudioDBRequest("reject_suggestion", refFromPB(r.Entry))
+	req.ExtDB, req.ExtID = r.ExtDb, r.ExtId
+	if err := s.execute(ctx, req); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *grpcService) GetEntryHistory(
+	ctx context.Context, ref *pb.EntryRef) (*pb.EntryHistory, error) {
+
+	req := NewAudioDBRequest("get_entry_history", refFromPB(ref))
+	if err := s.execute(ctx, req); err != nil {
+		return nil, err
+	}
+	ret := &pb.EntryHistory{}
+	for _, rev := range req.Revisions {
+		ret.Revisions = append(ret.Revisions, revisionToPB(rev))
+	}
+	return ret, nil
+}
+
+func (s *grpcService) DiffEntryRevisions(
+	ctx context.Context, r *pb.DiffRequest) (*pb.DiffResponse, error) {
+
+	req := NewAudioDBRequest("diff_entry_revisions", refFromPB(r.Entry))
+	req.RevisionID, req.ToRevisionID = int(r.RevisionId), int(r.ToRevisionId)
+	if err := s.execute(ctx, req); err != nil {
+		return nil, err
+	}
+	return changesToPB(req.Diff), nil
+}
+
+func (s *grpcService) RevertEntry(ctx context.Context, r *pb.RevertRequest) (*pb.EntryData, error) {
+	req := NewAudioDBRequest("revert_entry", refFromPB(r.Entry))
+	req.RevisionID = int(r.RevisionId)
+	if err := s.execute(ctx, req); err != nil {
+		return nil, err
+	}
+	return entryDataToPB(req), nil
+}
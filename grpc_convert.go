@@ -0,0 +1,341 @@
+package dbm
+
+import (
+	"encoding/json"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/ytsiuryn/ds-audiodbm/audiodbpb"
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+)
+
+// Соответствие статусов каталога значениям перечисления protobuf.
+var pbStatuses = map[string]pb.EntryStatus{
+	entity.StatusWithoutMandatoryTags: pb.EntryStatus_ENTRY_STATUS_WITHOUT_MANDATORY_TAGS,
+	entity.StatusWithMandatoryTags:    pb.EntryStatus_ENTRY_STATUS_WITH_MANDATORY_TAGS,
+	entity.StatusFinalyzed:            pb.EntryStatus_ENTRY_STATUS_FINALYZED,
+}
+
+func statusToPB(status string) pb.EntryStatus {
+	return pbStatuses[status]
+}
+
+func statusFromPB(status pb.EntryStatus) string {
+	for s, v := range pbStatuses {
+		if v == status {
+			return s
+		}
+	}
+	return ""
+}
+
+func timeToPB(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func timeFromPB(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func timePtrFromPB(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+func entryToPB(ent *entity.AlbumEntry) *pb.AlbumEntry {
+	if ent == nil {
+		return nil
+	}
+	ret := &pb.AlbumEntry{
+		Id:           int64(ent.ID),
+		Path:         ent.Path,
+		Json:         ent.Json,
+		Status:       statusToPB(ent.Status),
+		LastModified: timeToPB(ent.LastModified),
+		Version:      int32(ent.Version),
+	}
+	if ent.StaleSince != nil {
+		ret.StaleSince = timestamppb.New(*ent.StaleSince)
+	}
+	return ret
+}
+
+func entryFromPB(ent *pb.AlbumEntry) *entity.AlbumEntry {
+	if ent == nil {
+		return nil
+	}
+	return &entity.AlbumEntry{
+		ID:           int(ent.Id),
+		Path:         ent.Path,
+		Json:         ent.Json,
+		Status:       statusFromPB(ent.Status),
+		LastModified: timeFromPB(ent.LastModified),
+		Version:      int(ent.Version),
+		StaleSince:   timePtrFromPB(ent.StaleSince),
+	}
+}
+
+func entriesToPB(entries []*entity.AlbumEntry) []*pb.AlbumEntry {
+	var ret []*pb.AlbumEntry
+	for _, ent := range entries {
+		ret = append(ret, entryToPB(ent))
+	}
+	return ret
+}
+
+// Преобразует ссылку на каталог в запись Entry запроса.
+func refFromPB(ref *pb.EntryRef) *entity.AlbumEntry {
+	if ref == nil {
+		return nil
+	}
+	return &entity.AlbumEntry{ID: int(ref.Id), Path: ref.Path, Version: int(ref.Version)}
+}
+
+func actorToPB(actor *entity.Actor) *pb.Actor {
+	ret := &pb.Actor{
+		EntryId:    int64(actor.EntryID),
+		Name:       actor.Name,
+		EntityMask: uint32(actor.EntityMask),
+	}
+	for _, pair := range actor.IDs {
+		ret.Ids = append(ret.Ids, &pb.ActorID{ExtDb: pair[0], Id: pair[1]})
+	}
+	return ret
+}
+
+func actorFromPB(actor *pb.Actor) *entity.Actor {
+	ret := &entity.Actor{
+		EntryID:    int(actor.EntryId),
+		Name:       actor.Name,
+		EntityMask: entity.EntityMask(actor.EntityMask),
+	}
+	for _, id := range actor.Ids {
+		ret.IDs = append(ret.IDs, [2]string{id.ExtDb, id.Id})
+	}
+	return ret
+}
+
+func pictureToPB(p *entity.Picture) *pb.Picture {
+	return &pb.Picture{
+		EntityType: p.EntType,
+		EntityId:   int64(p.EntID),
+		PictType:   p.PictType,
+		Width:      int32(p.Width),
+		Height:     int32(p.Height),
+		Mime:       p.Mime,
+		Notes:      p.Notes,
+		Data:       p.Data,
+	}
+}
+
+func pictureFromPB(p *pb.Picture) *entity.Picture {
+	return &entity.Picture{
+		EntType:  p.EntityType,
+		EntID:    int(p.EntityId),
+		PictType: p.PictType,
+		Width:    int(p.Width),
+		Height:   int(p.Height),
+		Mime:     p.Mime,
+		Notes:    p.Notes,
+		Data:     p.Data,
+	}
+}
+
+func suggestionToPB(s *entity.Suggestion) *pb.Suggestion {
+	return &pb.Suggestion{
+		EntryId: int64(s.EntryID),
+		ExtDb:   s.ExtDB,
+		ExtId:   s.ExtID,
+		Json:    s.Json,
+		Score:   s.Score,
+	}
+}
+
+func suggestionFromPB(s *pb.Suggestion) *entity.Suggestion {
+	return &entity.Suggestion{
+		EntryID: int(s.EntryId),
+		ExtDB:   s.ExtDb,
+		ExtID:   s.ExtId,
+		Json:    s.Json,
+		Score:   s.Score,
+	}
+}
+
+func badSuggestionToPB(bs *entity.BadSuggestion) *pb.BadSuggestion {
+	return &pb.BadSuggestion{EntryId: int64(bs.EntryID), ExtDb: bs.ExtDB, ExtId: bs.ExtID}
+}
+
+func badSuggestionFromPB(bs *pb.BadSuggestion) *entity.BadSuggestion {
+	return &entity.BadSuggestion{EntryID: int(bs.EntryId), ExtDB: bs.ExtDb, ExtID: bs.ExtId}
+}
+
+func acceptedToPB(as *entity.AcceptedSuggestion) *pb.AcceptedSuggestion {
+	if as == nil {
+		return nil
+	}
+	return &pb.AcceptedSuggestion{
+		EntryId:     int64(as.EntryID),
+		ExtDb:       as.ExtDB,
+		ExtId:       as.ExtID,
+		MergePolicy: as.MergePolicy,
+		AcceptedAt:  timeToPB(as.AcceptedAt),
+	}
+}
+
+func revisionToPB(r *entity.EntryRevision) *pb.EntryRevision {
+	return &pb.EntryRevision{
+		Id:           int64(r.ID),
+		EntryId:      int64(r.EntryID),
+		Path:         r.Path,
+		Json:         r.Json,
+		Status:       statusToPB(r.Status),
+		LastModified: timeToPB(r.LastModified),
+		Cmd:          r.Cmd,
+		CreatedAt:    timeToPB(r.CreatedAt),
+	}
+}
+
+// Формирует данные каталога из результата выполнения команды.
+func entryDataToPB(req *AudioDBRequest) *pb.EntryData {
+	if req == nil {
+		return nil
+	}
+	ret := &pb.EntryData{
+		Entry:       entryToPB(req.Entry),
+		Accepted:    acceptedToPB(req.Accepted),
+		Transitions: req.Transitions,
+		MissingTags: req.MissingTags,
+	}
+	for _, actor := range req.Actors {
+		ret.Actors = append(ret.Actors, actorToPB(actor))
+	}
+	for _, p := range req.Pictures {
+		ret.Pictures = append(ret.Pictures, pictureToPB(p))
+	}
+	for _, s := range req.Suggestions {
+		ret.Suggestions = append(ret.Suggestions, suggestionToPB(s))
+	}
+	for _, bs := range req.BadSuggestions {
+		ret.BadSuggestions = append(ret.BadSuggestions, badSuggestionToPB(bs))
+	}
+	return ret
+}
+
+// Формирует запрос команды `cmd` из данных каталога.
+func requestFromEntryData(cmd string, data *pb.EntryData) *AudioDBRequest {
+	req := NewAudioDBRequest(cmd, entryFromPB(data.Entry))
+	for _, actor := range data.Actors {
+		req.Actors = append(req.Actors, actorFromPB(actor))
+	}
+	for _, p := range data.Pictures {
+		req.Pictures = append(req.Pictures, pictureFromPB(p))
+	}
+	for _, s := range data.Suggestions {
+		req.Suggestions = append(req.Suggestions, suggestionFromPB(s))
+	}
+	for _, bs := range data.BadSuggestions {
+		req.BadSuggestions = append(req.BadSuggestions, badSuggestionFromPB(bs))
+	}
+	return req
+}
+
+func filterFromPB(f *pb.EntryFilter) *entity.EntryFilter {
+	ret := &entity.EntryFilter{
+		Status:     statusFromPB(f.Status),
+		PathPrefix: f.PathPrefix,
+		SortBy:     f.SortBy,
+		Desc:       f.Desc,
+		Limit:      int(f.Limit),
+		Cursor:     f.Cursor,
+	}
+	if f.ModifiedAfter != nil {
+		ret.ModifiedAfter = timePtrFromPB(f.ModifiedAfter)
+	}
+	if f.ModifiedBefore != nil {
+		ret.ModifiedBefore = timePtrFromPB(f.ModifiedBefore)
+	}
+	if f.HasSuggestions != nil {
+		has := *f.HasSuggestions
+		ret.HasSuggestions = &has
+	}
+	return ret
+}
+
+func searchFromPB(s *pb.SearchRequest) *entity.EntrySearch {
+	return &entity.EntrySearch{
+		Query:  s.Query,
+		Fields: s.Fields,
+		Genre:  s.Genre,
+		Label:  s.Label,
+		Catno:  s.Catno,
+		Limit:  int(s.Limit),
+		Offset: int(s.Offset),
+	}
+}
+
+func searchResultsToPB(results []*entity.SearchResult) *pb.SearchResponse {
+	ret := &pb.SearchResponse{}
+	for _, res := range results {
+		ret.Results = append(ret.Results, &pb.SearchResult{
+			Entry:    entryToPB(res.Entry),
+			Rank:     res.Rank,
+			Headline: res.Headline,
+		})
+	}
+	return ret
+}
+
+func movesToPB(req *AudioDBRequest) *pb.MoveTreeResponse {
+	ret := &pb.MoveTreeResponse{Collisions: req.Collisions}
+	for _, move := range req.Moves {
+		ret.Moves = append(ret.Moves, &pb.EntryMove{
+			EntryId: int64(move.EntryID),
+			OldPath: move.OldPath,
+			NewPath: move.NewPath,
+		})
+	}
+	return ret
+}
+
+func reconcileReportToPB(report *ReconcileReport) *pb.ReconcileReport {
+	if report == nil {
+		return &pb.ReconcileReport{}
+	}
+	return &pb.ReconcileReport{
+		Missing:      report.Missing,
+		Orphans:      entriesToPB(report.Orphans),
+		Modified:     entriesToPB(report.Modified),
+		Restored:     entriesToPB(report.Restored),
+		OrphanAction: report.OrphanAction,
+		StartedAt:    timeToPB(report.StartedAt),
+		FinishedAt:   timeToPB(report.FinishedAt),
+	}
+}
+
+func changesToPB(changes []*JSONChange) *pb.DiffResponse {
+	ret := &pb.DiffResponse{}
+	for _, change := range changes {
+		ret.Changes = append(ret.Changes, &pb.JSONChange{
+			Path: change.Path,
+			Op:   change.Op,
+			Old:  []byte(change.Old),
+			New:  []byte(change.New),
+		})
+	}
+	return ret
+}
+
+// Проверяет, что данные релиза являются корректным JSON.
+func validJSON(data []byte) bool {
+	return len(data) == 0 || json.Valid(data)
+}
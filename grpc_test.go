@@ -0,0 +1,152 @@
+package dbm
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	pb "github.com/ytsiuryn/ds-audiodbm/audiodbpb"
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+	md "github.com/ytsiuryn/ds-audiomd"
+)
+
+// Запускает сервер gRPC сервиса `m` в памяти и возвращает подключенного к нему клиента.
+func newTestGRPCClient(t *testing.T, m *Dbm) pb.AudioDBClient {
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	m.RegisterGRPC(s)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithInsecure())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewAudioDBClient(conn)
+}
+
+func TestGRPCService(t *testing.T) {
+	m := New("", WithStore(entity.NewMemStore()))
+	defer m.Close()
+	cl := newTestGRPCClient(t, m)
+	ctx := context.Background()
+
+	_, err := cl.Ping(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+
+	data, err := ioutil.ReadFile("testdata/test_assumption.json")
+	require.NoError(t, err)
+	testAssumption := md.NewAssumption(nil)
+	require.NoError(t, json.Unmarshal(data, &testAssumption))
+	req := NewAudioDBRequest("set_entry", &entity.AlbumEntry{Path: "test"})
+	require.NoError(t, req.ImportAssumption(testAssumption))
+
+	created, err := cl.SetEntry(ctx, entryDataToPB(req))
+	require.NoError(t, err)
+	require.NotZero(t, created.Entry.Id)
+	assert.Equal(t, pb.EntryStatus_ENTRY_STATUS_WITH_MANDATORY_TAGS, created.Entry.Status)
+	for _, path := range []string{"test2", "test3"} {
+		_, err = cl.SetEntry(ctx, &pb.EntryData{Entry: &pb.AlbumEntry{Path: path}})
+		require.NoError(t, err)
+	}
+
+	got, err := cl.GetEntry(ctx, &pb.EntryRef{Path: "test"})
+	require.NoError(t, err)
+	assert.Equal(t, created.Entry.Id, got.Entry.Id)
+	require.Len(t, got.Actors, 1)
+	assert.NotEmpty(t, got.Actors[0].Ids)
+	require.Len(t, got.Pictures, 1)
+	assert.Equal(t, testAssumption.Pictures[0].Data, got.Pictures[0].Data)
+	assert.JSONEq(t, string(req.Entry.Json), string(got.Entry.Json))
+
+	_, err = cl.SetEntry(ctx, &pb.EntryData{Entry: &pb.AlbumEntry{Path: "x", Json: []byte("{")}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = cl.FinalyzeEntry(ctx, &pb.EntryRef{Id: got.Entry.Id, Version: got.Entry.Version + 1})
+	require.Equal(t, codes.Aborted, status.Code(err))
+	details := status.Convert(err).Details()
+	require.Len(t, details, 1)
+	detail := details[0].(*pb.ErrorDetail)
+	assert.Equal(t, string(CodeConflict), detail.Code)
+	assert.Equal(t, "test", detail.State.Entry.Path)
+
+	renamed, err := cl.RenameEntry(ctx, &pb.RenameEntryRequest{
+		Entry: &pb.EntryRef{Id: got.Entry.Id}, NewPath: "test1"})
+	require.NoError(t, err)
+	assert.Equal(t, "test1", renamed.Path)
+
+	stream, err := cl.ListEntries(ctx, &pb.EntryFilter{PathPrefix: "test", SortBy: "path", Limit: 1})
+	require.NoError(t, err)
+	var paths []string
+	for {
+		ent, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		paths = append(paths, ent.Path)
+	}
+	assert.Equal(t, []string{"test1", "test2", "test3"}, paths)
+
+	export, err := cl.ExportEntries(ctx, &pb.EntryFilter{PathPrefix: "test1"})
+	require.NoError(t, err)
+	exported, err := export.Recv()
+	require.NoError(t, err)
+	assert.Len(t, exported.Pictures, 1)
+	_, err = export.Recv()
+	assert.Equal(t, io.EOF, err)
+
+	_, err = cl.DeleteEntry(ctx, &pb.EntryRef{Id: got.Entry.Id})
+	require.NoError(t, err)
+	_, err = cl.GetEntry(ctx, &pb.EntryRef{Id: got.Entry.Id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGRPCServiceDeadline(t *testing.T) {
+	m := New("", WithStore(entity.NewMemStore()), WithWorkers(1))
+	defer m.Close()
+	cl := newTestGRPCClient(t, m)
+
+	// единственный обработчик сервиса занят запросом другого транспорта
+	release := make(chan struct{})
+	m.disp.Dispatch("", func() { <-release })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := cl.SetEntry(ctx, &pb.EntryData{Entry: &pb.AlbumEntry{Path: "a"}})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	// срок действия вызова на стороне сервера истекает вслед за сроком клиента
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	m.disp.Wait()
+
+	// команда с истекшим сроком действия не выполнена
+	_, err = cl.GetEntry(context.Background(), &pb.EntryRef{Path: "a"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// выборка прекращается при отмене вызова
+	_, err = cl.SetEntry(context.Background(), &pb.EntryData{Entry: &pb.AlbumEntry{Path: "a"}})
+	require.NoError(t, err)
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	stream, err := cl.ListEntries(ctx, &pb.EntryFilter{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	assert.Equal(t, codes.Canceled, status.Code(err))
+}
@@ -0,0 +1,102 @@
+package dbm
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+	srv "github.com/ytsiuryn/ds-microservice"
+)
+
+// Handle выполняет запрос к сервису в формате JSON и возвращает ответ в формате JSON.
+// Обработчик не зависит от способа доставки запросов: ошибки выполнения команды
+// возвращаются в ответе так же, как при работе через брокер сообщений.
+// Ответ на команду ping, как и при работе через брокер сообщений, пуст.
+// Handle не использует диспетчер сервиса: упорядочивание команд одного Entry и
+// ограничение числа одновременно выполняемых запросов обеспечивает вызывающий код
+// (см. InProcTransport).
+func (m *Dbm) Handle(body []byte) []byte {
+	var req AudioDBRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return m.errorAnswer(nil, err, "Message dispatcher")
+	}
+	m.logRequest(&req)
+	return m.handle(&req)
+}
+
+// Выполняет команду запроса и формирует ответ клиенту.
+func (m *Dbm) handle(req *AudioDBRequest) []byte {
+	data, _ := m.respond(req)
+	return data
+}
+
+// Выполняет команду запроса и возвращает ответ клиенту вместе с ошибкой выполнения
+// команды, сведения о которой уже включены в ответ.
+func (m *Dbm) respond(req *AudioDBRequest) ([]byte, error) {
+	if req.Cmd == "ping" {
+		return []byte{}, nil
+	}
+
+	data, err := m.Execute(req)
+	switch {
+	case errors.Is(err, ErrUnknownCommand):
+		return m.baseErrorAnswer(err, "Message dispatcher"), err
+	case errors.Is(err, entity.ErrVersionConflict):
+		return m.conflictAnswer(req, err), err
+	case errors.Is(err, ErrPathExists) && len(req.Collisions) > 0:
+		// перемещение дерева каталогов: ответ содержит план перемещения и коллизии путей
+		return m.errorAnswer(req, err, req.Cmd), err
+	case err != nil:
+		return m.errorAnswer(nil, err, req.Cmd), err
+	}
+	return data, nil
+}
+
+// Формирует ответ с информацией об ошибке и, при наличии, данными `state`.
+func (m *Dbm) errorAnswer(state *AudioDBRequest, err error, context string) []byte {
+	m.LogOnErrorWithContext(err, context)
+	resp := &AudioDBResponse{
+		AudioDBRequest: state,
+		Error: &ErrorResponse{
+			ErrorResponse: srv.ErrorResponse{
+				Error:   err.Error(),
+				Context: context,
+			},
+			Code: errorCode(err),
+		},
+	}
+	data, err := json.Marshal(resp)
+	srv.FailOnError(err, "Answer marshalling")
+	return data
+}
+
+// Формирует ответ с ошибкой конфликта версий Entry.
+// Ответ содержит текущее состояние Entry в БД, что позволяет клиенту повторно объединить
+// свои изменения с изменениями другого клиента.
+func (m *Dbm) conflictAnswer(req *AudioDBRequest, err error) []byte {
+	return m.errorAnswer(m.conflictState(req), err, req.Cmd)
+}
+
+// Возвращает текущее состояние Entry запроса, завершившегося конфликтом версий,
+// или nil, если состояние не удалось прочитать.
+func (m *Dbm) conflictState(req *AudioDBRequest) *AudioDBRequest {
+	state := NewAudioDBRequest(
+		req.Cmd, &entity.AlbumEntry{ID: req.Entry.ID, Path: req.Entry.Path})
+	if err := m.loadEntry(state); err != nil {
+		m.LogOnErrorWithContext(err, req.Cmd)
+		return nil
+	}
+	return state
+}
+
+// Формирует ответ с ошибкой в формате `srv.ErrorResponse`, дополненном кодом ошибки,
+// для команд, не относящихся к работе с БД.
+func (m *Dbm) baseErrorAnswer(err error, context string) []byte {
+	m.LogOnErrorWithContext(err, context)
+	data, err := json.Marshal(&ErrorResponse{
+		ErrorResponse: srv.ErrorResponse{Error: err.Error(), Context: context},
+		Code:          errorCode(err)})
+	srv.FailOnError(err, "Answer marshalling")
+	return data
+}
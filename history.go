@@ -0,0 +1,219 @@
+package dbm
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/pkg/errors"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+)
+
+// ErrRevisionMismatch возвращается, если ревизия относится к другому Entry.
+var ErrRevisionMismatch = errors.New("revision does not belong to entry")
+
+// getEntryHistory возвращает список ревизий Entry, начиная с последней.
+func (m *Dbm) getEntryHistory(req *AudioDBRequest) (_ []byte, err error) {
+	if err = m.store.GetEntry(m.ctx, req.Entry); err != nil {
+		return
+	}
+	req.Revisions, err = m.store.EntryRevisions(m.ctx, req.Entry.ID)
+	if err != nil {
+		return
+	}
+	return json.Marshal(req)
+}
+
+// diffEntryRevisions возвращает изменения между ревизиями `req.RevisionID` и
+// `req.ToRevisionID` Entry. Нулевое значение `req.ToRevisionID` соответствует текущему
+// состоянию Entry.
+func (m *Dbm) diffEntryRevisions(req *AudioDBRequest) (_ []byte, err error) {
+	if err = m.store.GetEntry(m.ctx, req.Entry); err != nil {
+		return
+	}
+	from, err := m.entryRevision(req.Entry, req.RevisionID)
+	if err != nil {
+		return
+	}
+	to, err := m.entryRevision(req.Entry, req.ToRevisionID)
+	if err != nil {
+		return
+	}
+	req.Diff, err = diffRevisions(from, to)
+	if err != nil {
+		return
+	}
+	req.Entry.Json = nil
+	return json.Marshal(req)
+}
+
+// revertEntry возвращает Entry к состоянию ревизии `req.RevisionID`.
+// Вместе с Entry восстанавливаются акторы каталога и принятое предложение, если ревизия
+// содержит их снимок. Текущее состояние Entry предварительно сохраняется в виде новой
+// ревизии, поэтому откат также может быть отменен.
+func (m *Dbm) revertEntry(req *AudioDBRequest) (_ []byte, err error) {
+	txctx, tx, err := m.begin(m.ctx)
+	if err != nil {
+		return
+	}
+	defer m.completeTx(tx, &err)
+
+	expectedVersion := req.Entry.Version
+	if err = m.store.GetEntry(txctx, req.Entry); err != nil {
+		return
+	}
+	if err = checkNotFinalyzed(req.Entry); err != nil {
+		return
+	}
+	expectVersion(req.Entry, expectedVersion)
+	rev, err := m.entryRevision(req.Entry, req.RevisionID)
+	if err != nil {
+		return
+	}
+	if err = checkEditStatus(req.Entry.Status, rev.Status); err != nil {
+		return
+	}
+	if err = m.store.SaveEntryRevision(txctx, req.Entry.ID, req.Cmd); err != nil {
+		return
+	}
+
+	old := *req.Entry
+	req.Entry.Path = rev.Path
+	req.Entry.Json = rev.Json
+	req.Entry.Status = rev.Status
+	req.Entry.LastModified = rev.LastModified
+	if err = m.store.UpdateEntry(txctx, req.Entry); err != nil {
+		return
+	}
+	changed := entryChanges(&old, req.Entry)
+	if rev.Actors != nil {
+		var refs []string
+		if refs, err = m.restoreEntryRefs(txctx, rev); err != nil {
+			return
+		}
+		changed = append(changed, refs...)
+	}
+
+	ev := &entity.EntryEvent{
+		Type:      entity.EntryUpdated,
+		Cmd:       req.Cmd,
+		EntryID:   req.Entry.ID,
+		Path:      req.Entry.Path,
+		OldStatus: old.Status,
+		NewStatus: req.Entry.Status,
+		Changed:   changed}
+	if old.Path != req.Entry.Path {
+		ev.OldPath = old.Path
+	}
+	if err = m.recordEvent(txctx, ev); err != nil {
+		return
+	}
+
+	return json.Marshal(req)
+}
+
+// Восстанавливает акторов каталога и принятое предложение по снимку ревизии `rev`.
+// Признак AlbumEntryEntity и идентификаторы акторов каталога берутся из снимка, а
+// признак SuggestionEntity сохраняется текущим, так как предложения откатом не затрагиваются.
+// Возвращает названия изменившихся полей ("actors", "accepted").
+func (m *Dbm) restoreEntryRefs(ctx context.Context, rev *entity.EntryRevision) (
+	changed []string, err error) {
+
+	actors, err := m.store.EntryActors(ctx, rev.EntryID)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := map[string]*entity.Actor{}
+	for _, actor := range rev.Actors {
+		if actor.EntityMask&entity.AlbumEntryEntity != 0 {
+			snapshot[actor.Name] = actor
+		}
+	}
+	actorsChanged := false
+	for _, actor := range actors {
+		saved, ok := snapshot[actor.Name]
+		delete(snapshot, actor.Name)
+		restored := *actor
+		restored.EntityMask &^= entity.AlbumEntryEntity
+		if ok {
+			restored.EntityMask |= entity.AlbumEntryEntity
+			restored.IDs = saved.IDs
+		}
+		switch {
+		case restored.EntityMask == 0:
+			err = m.store.DeleteActor(ctx, actor)
+		case restored.EntityMask != actor.EntityMask || !reflect.DeepEqual(restored.IDs, actor.IDs):
+			err = m.store.UpdateActor(ctx, &restored)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		actorsChanged = true
+	}
+	for _, saved := range rev.Actors {
+		if _, ok := snapshot[saved.Name]; !ok {
+			continue
+		}
+		actor := &entity.Actor{
+			EntryID:    rev.EntryID,
+			Name:       saved.Name,
+			IDs:        saved.IDs,
+			EntityMask: entity.AlbumEntryEntity}
+		if err = m.store.CreateActor(ctx, actor); err != nil {
+			return nil, err
+		}
+		actorsChanged = true
+	}
+	if actorsChanged {
+		changed = append(changed, "actors")
+	}
+
+	cur := &entity.AcceptedSuggestion{EntryID: rev.EntryID}
+	if err = m.store.GetAcceptedSuggestion(ctx, cur); err != nil {
+		if !errors.Is(err, entity.ErrNotFound) {
+			return nil, err
+		}
+		cur = nil
+	}
+	switch {
+	case rev.Accepted == nil && cur == nil:
+		return changed, nil
+	case rev.Accepted == nil:
+		err = m.store.DeleteEntryAcceptedSuggestion(ctx, rev.EntryID)
+	case cur != nil && cur.ExtDB == rev.Accepted.ExtDB && cur.ExtID == rev.Accepted.ExtID &&
+		cur.MergePolicy == rev.Accepted.MergePolicy && cur.AcceptedAt.Equal(rev.Accepted.AcceptedAt):
+		return changed, nil
+	default:
+		accepted := *rev.Accepted
+		accepted.EntryID = rev.EntryID
+		err = m.store.SaveAcceptedSuggestion(ctx, &accepted)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(changed, "accepted"), nil
+}
+
+// Возвращает ревизию Entry с указанным ID или текущее состояние Entry при нулевом ID.
+func (m *Dbm) entryRevision(entry *entity.AlbumEntry, revisionID int) (*entity.EntryRevision, error) {
+	if revisionID == 0 {
+		return &entity.EntryRevision{
+			EntryID:      entry.ID,
+			Path:         entry.Path,
+			Json:         entry.Json,
+			Status:       entry.Status,
+			LastModified: entry.LastModified}, nil
+	}
+	rev := &entity.EntryRevision{ID: revisionID}
+	if err := m.store.GetEntryRevision(m.ctx, rev); err != nil {
+		return nil, err
+	}
+	if rev.EntryID != entry.ID {
+		return nil, errors.Wrapf(ErrRevisionMismatch, "revision_id=%d, entry_id=%d",
+			revisionID, entry.ID)
+	}
+	return rev, nil
+}
@@ -0,0 +1,80 @@
+package dbm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+)
+
+func TestRevertAcceptedSuggestion(t *testing.T) {
+	ctx := context.Background()
+	store := entity.NewMemStore()
+	m := New("", WithStore(store))
+	defer m.Close()
+
+	entry := executeCmd(t, m, NewAudioDBRequest("set_entry", &entity.AlbumEntry{
+		Path: "a",
+		Json: []byte(`{"title":"Album","actors":{"Artist":{}}}`)})).Entry
+	txctx, tx, err := store.Begin(ctx)
+	require.NoError(t, err)
+	for _, actor := range []*entity.Actor{
+		{EntryID: entry.ID, Name: "Artist", EntityMask: entity.AlbumEntryEntity},
+		{EntryID: entry.ID, Name: "Guest", IDs: [][2]string{{"discogs", "1"}},
+			EntityMask: entity.SuggestionEntity},
+	} {
+		require.NoError(t, store.CreateActor(txctx, actor))
+	}
+	require.NoError(t, store.CreateSuggestion(txctx, &entity.Suggestion{
+		EntryID: entry.ID,
+		ExtDB:   "discogs",
+		ExtID:   "1",
+		Json:    []byte(`{"title":"Album","actors":{"Guest":{}}}`)}))
+	require.NoError(t, tx.Commit(ctx))
+	before, err := store.EntryActors(ctx, entry.ID)
+	require.NoError(t, err)
+
+	acceptReq := NewAudioDBRequest("accept_suggestion", &entity.AlbumEntry{ID: entry.ID})
+	acceptReq.ExtDB, acceptReq.ExtID = "discogs", "1"
+	answ := executeCmd(t, m, acceptReq)
+	require.NotNil(t, answ.Accepted)
+	accepted := entity.AlbumEntryEntity | entity.SuggestionEntity
+	assert.Equal(t, accepted, actorMask(answ.Actors, "Guest"))
+
+	history := executeCmd(t, m, NewAudioDBRequest("get_entry_history", &entity.AlbumEntry{ID: entry.ID}))
+	require.Len(t, history.Revisions, 1)
+	revertReq := NewAudioDBRequest("revert_entry", &entity.AlbumEntry{ID: entry.ID})
+	revertReq.RevisionID = history.Revisions[0].ID
+	executeCmd(t, m, revertReq)
+
+	after, err := store.EntryActors(ctx, entry.ID)
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+	err = store.GetAcceptedSuggestion(ctx, &entity.AcceptedSuggestion{EntryID: entry.ID})
+	assert.ErrorIs(t, err, entity.ErrNotFound)
+
+	// повторный откат возвращает принятое предложение
+	history = executeCmd(t, m, NewAudioDBRequest("get_entry_history", &entity.AlbumEntry{ID: entry.ID}))
+	require.Len(t, history.Revisions, 2)
+	revertReq.RevisionID = history.Revisions[0].ID
+	executeCmd(t, m, revertReq)
+	saved := &entity.AcceptedSuggestion{EntryID: entry.ID}
+	require.NoError(t, store.GetAcceptedSuggestion(ctx, saved))
+	assert.Equal(t, "1", saved.ExtID)
+	after, err = store.EntryActors(ctx, entry.ID)
+	require.NoError(t, err)
+	assert.Equal(t, accepted, actorMask(after, "Guest"))
+}
+
+// Возвращает маску актора с именем `name` (0 при его отсутствии).
+func actorMask(actors []*entity.Actor, name string) entity.EntityMask {
+	for _, actor := range actors {
+		if actor.Name == name {
+			return actor.EntityMask
+		}
+	}
+	return 0
+}
@@ -0,0 +1,413 @@
+package dbm
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+)
+
+// Параметры HTTP-сервера.
+const (
+	httpReadTimeout     = 30 * time.Second
+	httpShutdownTimeout = 10 * time.Second
+	httpMaxBodySize     = 64 << 20
+	httpContext         = "HTTP gateway"
+)
+
+// Ошибки разбора HTTP-запроса.
+var (
+	ErrMethodNotAllowed = errors.Wrap(ErrBadRequest, "method not allowed")
+	ErrBadEntryID       = errors.Wrap(ErrBadRequest, "bad entry id")
+)
+
+// Соответствие кодов ошибок выполнения команд кодам состояния HTTP.
+var httpStatuses = map[ErrorCode]int{
+	CodeNotFound:         http.StatusNotFound,
+	CodeConflict:         http.StatusConflict,
+	CodeValidationFailed: http.StatusUnprocessableEntity,
+	CodeAlreadyFinalyzed: http.StatusConflict,
+	CodePathExists:       http.StatusConflict,
+	CodeDBUnavailable:    http.StatusServiceUnavailable,
+	CodeBadRequest:       http.StatusBadRequest,
+	CodeInternal:         http.StatusInternalServerError,
+}
+
+// WithHTTP включает HTTP-шлюз (REST) к каталогу по адресу `addr` (например, ":8080")
+// наряду с обработкой запросов от брокера сообщений.
+func WithHTTP(addr string) Option {
+	return func(m *Dbm) {
+		m.httpAddr = addr
+	}
+}
+
+// httpGateway предоставляет каталог в виде ресурсов REST:
+//
+//	GET    /entries?path=<path>                     каталог по пути
+//	GET    /entries?prefix=&status=&modified_after=&modified_before=&sort=&desc=&limit=&cursor=&has_suggestions=
+//	POST   /entries                                 создание каталога
+//	GET    /entries/{id}                            каталог с акторами, изображениями и предложениями
+//	PUT    /entries/{id}                            изменение каталога (set_entry)
+//	DELETE /entries/{id}                            удаление каталога
+//	GET    /entries/{id}/pictures/{pict_type}       изображение в двоичном виде
+//	GET    /entries/{id}/suggestions                предложения метаданных
+//	POST   /entries/{id}/finalyze                   финализация каталога
+//	POST   /entries/{id}/rename                     переименование ({"new_path": ...})
+//
+// Тело запросов PUT и POST имеет формат AudioDBRequest, ответы - формат AudioDBResponse.
+// Команды выполняются диспетчером сервиса так же, как запросы, полученные от брокера
+// сообщений. Команда запроса, клиент которого отключился до начала ее выполнения,
+// не выполняется.
+type httpGateway struct {
+	m *Dbm
+}
+
+// HTTPHandler возвращает обработчик HTTP-запросов к каталогу (см. WithHTTP).
+// Обработчик может быть встроен в HTTP-сервер приложения.
+func (m *Dbm) HTTPHandler() http.Handler {
+	return &httpGateway{m: m}
+}
+
+// Запускает HTTP-сервер шлюза.
+func (m *Dbm) startHTTP() {
+	m.httpServer = &http.Server{
+		Addr:              m.httpAddr,
+		Handler:           m.HTTPHandler(),
+		ReadHeaderTimeout: httpReadTimeout,
+	}
+	m.httpDone = make(chan struct{})
+
+	go func() {
+		defer close(m.httpDone)
+		m.Log.Infof("Serving HTTP requests on %s", m.httpAddr)
+		if err := m.httpServer.ListenAndServe(); err != http.ErrServerClosed {
+			m.LogOnErrorWithContext(err, httpContext)
+		}
+	}()
+}
+
+// Останавливает HTTP-сервер, ожидая завершения выполняемых запросов.
+func (m *Dbm) stopHTTP() {
+	ctx, cancel := context.WithTimeout(m.ctx, httpShutdownTimeout)
+	defer cancel()
+	m.LogOnErrorWithContext(m.httpServer.Shutdown(ctx), httpContext)
+	<-m.httpDone
+}
+
+func (g *httpGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if parts[0] != "entries" {
+		g.writeError(w, errors.Wrap(ErrNotFound, r.URL.Path))
+		return
+	}
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			g.getEntries(w, r)
+		case http.MethodPost:
+			g.createEntry(w, r)
+		default:
+			g.notAllowed(w, http.MethodGet, http.MethodPost)
+		}
+		return
+	}
+
+	id, err := strconv.Atoi(parts[1])
+	if err != nil || id <= 0 {
+		g.writeError(w, errors.Wrap(ErrBadEntryID, parts[1]))
+		return
+	}
+	entry := &entity.AlbumEntry{ID: id}
+
+	switch resource := strings.Join(parts[2:], "/"); {
+	case resource == "":
+		switch r.Method {
+		case http.MethodGet:
+			g.execute(w, r, NewAudioDBRequest("get_entry", entry))
+		case http.MethodPut:
+			g.executeWithBody(w, r, "set_entry", entry)
+		case http.MethodDelete:
+			g.execute(w, r, NewAudioDBRequest("delete_entry", entry))
+		default:
+			g.notAllowed(w, http.MethodGet, http.MethodPut, http.MethodDelete)
+		}
+	case resource == "suggestions":
+		if r.Method != http.MethodGet {
+			g.notAllowed(w, http.MethodGet)
+			return
+		}
+		g.getSuggestions(w, r, entry)
+	case len(parts) == 4 && parts[2] == "pictures":
+		if r.Method != http.MethodGet {
+			g.notAllowed(w, http.MethodGet)
+			return
+		}
+		g.getPicture(w, r, entry, parts[3])
+	case resource == "finalyze", resource == "rename":
+		if r.Method != http.MethodPost {
+			g.notAllowed(w, http.MethodPost)
+			return
+		}
+		g.executeWithBody(w, r, resource+"_entry", entry)
+	default:
+		g.writeError(w, errors.Wrap(ErrNotFound, r.URL.Path))
+	}
+}
+
+// Выполняет команду в порядке поступления команд для того же Entry.
+// Ожидание выполнения прекращается при отключении клиента.
+func (g *httpGateway) respond(ctx context.Context, req *AudioDBRequest) ([]byte, error) {
+	var data []byte
+	var cmdErr error
+	err := g.m.disp.DoContext(ctx, g.m.entryKey(req), func() {
+		g.m.logRequest(req)
+		data, cmdErr = g.m.respond(req)
+	})
+	if err != nil {
+		return g.m.errorAnswer(nil, err, httpContext), err
+	}
+	return data, cmdErr
+}
+
+// Читает данные каталога `entry` функцией `fn` в очереди команд этого каталога.
+// Перед вызовом `fn` запись каталога считывается из хранилища.
+func (g *httpGateway) read(
+	ctx context.Context, entry *entity.AlbumEntry, fn func(ctx context.Context) error) error {
+
+	var readErr error
+	err := g.m.disp.DoContext(ctx, g.m.entryKey(&AudioDBRequest{Entry: entry}), func() {
+		if readErr = g.m.store.GetEntry(g.m.ctx, entry); readErr == nil {
+			readErr = fn(g.m.ctx)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return readErr
+}
+
+// Выполняет команду и отправляет ответ в формате JSON.
+func (g *httpGateway) execute(w http.ResponseWriter, r *http.Request, req *AudioDBRequest) {
+	data, err := g.respond(r.Context(), req)
+	g.writeJSON(w, httpStatus(err), data)
+}
+
+// Выполняет команду с параметрами из тела запроса. ID записи задается путем ресурса.
+func (g *httpGateway) executeWithBody(
+	w http.ResponseWriter, r *http.Request, cmd string, entry *entity.AlbumEntry) {
+
+	req, err := g.readRequest(r)
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+	req.Cmd = cmd
+	if req.Entry == nil {
+		req.Entry = entry
+	}
+	req.Entry.ID = entry.ID
+	g.execute(w, r, req)
+}
+
+// Создает каталог; ответ содержит адрес ресурса созданного каталога.
+func (g *httpGateway) createEntry(w http.ResponseWriter, r *http.Request) {
+	req, err := g.readRequest(r)
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+	if req.Entry == nil || req.Entry.Path == "" {
+		g.writeError(w, ErrEntryRequired)
+		return
+	}
+	req.Cmd = "set_entry"
+	req.Entry.ID = 0
+
+	data, err := g.respond(r.Context(), req)
+	if err != nil {
+		g.writeJSON(w, httpStatus(err), data)
+		return
+	}
+	w.Header().Set("Location", "/entries/"+strconv.Itoa(req.Entry.ID))
+	g.writeJSON(w, http.StatusCreated, data)
+}
+
+// Возвращает каталог по пути или список каталогов по условиям фильтра.
+func (g *httpGateway) getEntries(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if path := q.Get("path"); path != "" {
+		g.execute(w, r, NewAudioDBRequest("get_entry", &entity.AlbumEntry{Path: path}))
+		return
+	}
+
+	filter, err := httpEntryFilter(q)
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+	req := NewAudioDBRequest("list_entries", nil)
+	req.Filter = filter
+	g.execute(w, r, req)
+}
+
+// Формирует фильтр списка каталогов из параметров запроса.
+func httpEntryFilter(q map[string][]string) (_ *entity.EntryFilter, err error) {
+	get := func(key string) string {
+		if v := q[key]; len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+	filter := &entity.EntryFilter{
+		Status:     get("status"),
+		PathPrefix: get("prefix"),
+		SortBy:     get("sort"),
+		Cursor:     get("cursor"),
+	}
+	if v := get("desc"); v != "" {
+		if filter.Desc, err = strconv.ParseBool(v); err != nil {
+			return nil, errors.Wrap(ErrBadRequest, "desc="+v)
+		}
+	}
+	if v := get("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil {
+			return nil, errors.Wrap(ErrBadRequest, "limit="+v)
+		}
+	}
+	for key, t := range map[string]**time.Time{
+		"modified_after":  &filter.ModifiedAfter,
+		"modified_before": &filter.ModifiedBefore,
+	} {
+		if v := get(key); v != "" {
+			tm, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return nil, errors.Wrap(ErrBadRequest, key+"="+v)
+			}
+			*t = &tm
+		}
+	}
+	if v := get("has_suggestions"); v != "" {
+		has, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, errors.Wrap(ErrBadRequest, "has_suggestions="+v)
+		}
+		filter.HasSuggestions = &has
+	}
+	return filter, nil
+}
+
+// Отправляет предложения метаданных каталога.
+func (g *httpGateway) getSuggestions(
+	w http.ResponseWriter, r *http.Request, entry *entity.AlbumEntry) {
+
+	resp := &AudioDBRequest{}
+	err := g.read(r.Context(), entry, func(ctx context.Context) (err error) {
+		if resp.Suggestions, err = g.m.store.EntrySuggestions(ctx, entry.ID); err != nil {
+			return
+		}
+		if resp.BadSuggestions, err = g.m.store.EntryBadSuggestions(ctx, entry.ID); err != nil {
+			return
+		}
+		accepted := &entity.AcceptedSuggestion{EntryID: entry.ID}
+		if err = g.m.store.GetAcceptedSuggestion(ctx, accepted); err == nil {
+			resp.Accepted = accepted
+		} else if !errors.Is(err, entity.ErrNotFound) {
+			return
+		}
+		return nil
+	})
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+	resp.Entry = &entity.AlbumEntry{ID: entry.ID, Path: entry.Path}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+	g.writeJSON(w, http.StatusOK, data)
+}
+
+// Отправляет данные изображения каталога с типом содержимого изображения.
+func (g *httpGateway) getPicture(
+	w http.ResponseWriter, r *http.Request, entry *entity.AlbumEntry, pictType string) {
+
+	var pict *entity.Picture
+	err := g.read(r.Context(), entry, func(ctx context.Context) error {
+		picts, err := g.m.store.EntryPictures(ctx, entry.ID)
+		for _, p := range picts {
+			if p.PictType == pictType {
+				pict = p
+				break
+			}
+		}
+		return err
+	})
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+	if pict == nil {
+		g.writeError(w, errors.Wrapf(ErrNotFound, "entry %d has no picture %s", entry.ID, pictType))
+		return
+	}
+	mime := pict.Mime
+	if mime == "" {
+		mime = http.DetectContentType(pict.Data)
+	}
+	w.Header().Set("Content-Type", mime)
+	w.Header().Set("Content-Length", strconv.Itoa(len(pict.Data)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(pict.Data)
+}
+
+// Читает параметры команды из тела запроса; пустое тело допустимо.
+func (g *httpGateway) readRequest(r *http.Request) (*AudioDBRequest, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r.Body, httpMaxBodySize))
+	if err != nil {
+		return nil, errors.Wrap(err, "request body reading failed")
+	}
+	req := &AudioDBRequest{}
+	if len(data) == 0 {
+		return req, nil
+	}
+	if err = json.Unmarshal(data, req); err != nil {
+		return nil, errors.Wrap(err, "request body decoding failed")
+	}
+	return req, nil
+}
+
+func (g *httpGateway) notAllowed(w http.ResponseWriter, methods ...string) {
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	g.writeError(w, ErrMethodNotAllowed)
+}
+
+func (g *httpGateway) writeError(w http.ResponseWriter, err error) {
+	g.writeJSON(w, httpStatus(err), g.m.errorAnswer(nil, err, httpContext))
+}
+
+func (g *httpGateway) writeJSON(w http.ResponseWriter, status int, data []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+// Возвращает код состояния HTTP для ошибки выполнения команды.
+func httpStatus(err error) int {
+	switch {
+	case err == nil:
+		return http.StatusOK
+	case errors.Is(err, ErrMethodNotAllowed):
+		return http.StatusMethodNotAllowed
+	}
+	return httpStatuses[errorCode(err)]
+}
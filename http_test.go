@@ -0,0 +1,164 @@
+package dbm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+	md "github.com/ytsiuryn/ds-audiomd"
+)
+
+func TestHTTPGateway(t *testing.T) {
+	m := New("", WithStore(entity.NewMemStore()))
+	defer m.Close()
+	ts := httptest.NewServer(m.HTTPHandler())
+	defer ts.Close()
+
+	data, err := ioutil.ReadFile("testdata/test_assumption.json")
+	require.NoError(t, err)
+	testAssumption := md.NewAssumption(nil)
+	require.NoError(t, json.Unmarshal(data, &testAssumption))
+	req := NewAudioDBRequest("", &entity.AlbumEntry{Path: "test"})
+	require.NoError(t, req.ImportAssumption(testAssumption))
+
+	resp, answ := httpDo(t, http.MethodPost, ts.URL+"/entries", req)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.NotZero(t, answ.Entry.ID)
+	location := resp.Header.Get("Location")
+	assert.Equal(t, "/entries/"+strconv.Itoa(answ.Entry.ID), location)
+
+	resp, answ = httpDo(t, http.MethodGet, ts.URL+location, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "test", answ.Entry.Path)
+	assert.Len(t, answ.Pictures, 1)
+	version := answ.Entry.Version
+
+	resp, answ = httpDo(t, http.MethodGet, ts.URL+"/entries?path=test", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, location, "/entries/"+strconv.Itoa(answ.Entry.ID))
+
+	resp, answ = httpDo(t, http.MethodGet, ts.URL+"/entries?prefix=te&limit=10", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, answ.Entries, 1)
+	resp, _ = httpDo(t, http.MethodGet, ts.URL+"/entries?limit=x", nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	for query, n := range map[string]int{
+		"modified_after=2000-01-01T00:00:00Z":  0,
+		"modified_before=2000-01-01T00:00:00Z": 1,
+	} {
+		resp, answ = httpDo(t, http.MethodGet, ts.URL+"/entries?"+query, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, query)
+		assert.Len(t, answ.Entries, n, query)
+	}
+	resp, _ = httpDo(t, http.MethodGet, ts.URL+"/entries?modified_after=yesterday", nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	pict, err := http.Get(ts.URL + location + "/pictures/cover_front")
+	require.NoError(t, err)
+	body, err := ioutil.ReadAll(pict.Body)
+	pict.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, pict.StatusCode)
+	assert.Equal(t, "image/jpeg", pict.Header.Get("Content-Type"))
+	assert.Equal(t, testAssumption.Pictures[0].Data, body)
+	resp, _ = httpDo(t, http.MethodGet, ts.URL+location+"/pictures/cover_back", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp, _ = httpDo(t, http.MethodGet, ts.URL+"/entries/999/pictures/cover_front", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, answ = httpDo(t, http.MethodGet, ts.URL+location+"/suggestions", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, answ.Suggestions)
+	assert.Empty(t, answ.Pictures)
+
+	stale := &AudioDBRequest{Entry: &entity.AlbumEntry{Path: "x", Version: version + 1}}
+	resp, answ = httpDo(t, http.MethodPut, ts.URL+location, stale)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.ErrorIs(t, answ.Err(), ErrConflict)
+	require.NotNil(t, answ.AudioDBRequest)
+	assert.Equal(t, "test", answ.Entry.Path)
+
+	resp, answ = httpDo(t, http.MethodPost, ts.URL+location+"/rename",
+		&AudioDBRequest{NewPath: "renamed"})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "renamed", answ.NewPath)
+	resp, _ = httpDo(t, http.MethodGet, ts.URL+"/entries?path=renamed", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, answ = httpDo(t, http.MethodPost, ts.URL+location+"/finalyze", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, entity.StatusFinalyzed, answ.Entry.Status)
+	resp, answ = httpDo(t, http.MethodPost, ts.URL+location+"/finalyze", nil)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.ErrorIs(t, answ.Err(), ErrAlreadyFinalyzed)
+
+	resp, _ = httpDo(t, http.MethodPost, ts.URL+location, nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	assert.Equal(t, "GET, PUT, DELETE", resp.Header.Get("Allow"))
+	resp, _ = httpDo(t, http.MethodGet, ts.URL+"/entries/abc", nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp, _ = httpDo(t, http.MethodGet, ts.URL+"/albums", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, _ = httpDo(t, http.MethodDelete, ts.URL+location, nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, answ = httpDo(t, http.MethodGet, ts.URL+location, nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.ErrorIs(t, answ.Err(), ErrNotFound)
+}
+
+func TestHTTPGatewayClientGone(t *testing.T) {
+	m := New("", WithStore(entity.NewMemStore()), WithWorkers(1))
+	defer m.Close()
+
+	// единственный обработчик сервиса занят запросом другого транспорта
+	release := make(chan struct{})
+	m.disp.Dispatch("", func() { <-release })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	body := strings.NewReader(`{"entry":{"path":"a","status":"without_mandatory_tags"}}`)
+	r := httptest.NewRequest(http.MethodPost, "/entries", body).WithContext(ctx)
+	w := httptest.NewRecorder()
+	m.HTTPHandler().ServeHTTP(w, r)
+	assert.NotEqual(t, http.StatusCreated, w.Code)
+	close(release)
+	m.disp.Wait()
+
+	// команда отключившегося клиента не выполнена
+	r = httptest.NewRequest(http.MethodGet, "/entries?path=a", nil)
+	w = httptest.NewRecorder()
+	m.HTTPHandler().ServeHTTP(w, r)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// Выполняет HTTP-запрос с телом `req` в формате JSON и разбирает ответ.
+func httpDo(
+	t *testing.T, method, url string, req *AudioDBRequest) (*http.Response, *AudioDBResponse) {
+
+	var body bytes.Buffer
+	if req != nil {
+		require.NoError(t, json.NewEncoder(&body).Encode(req))
+	}
+	r, err := http.NewRequest(method, url, &body)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(r)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.True(t, strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json"))
+
+	var answ AudioDBResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&answ))
+	return resp, &answ
+}
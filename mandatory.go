@@ -0,0 +1,132 @@
+package dbm
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+	md "github.com/ytsiuryn/ds-audiomd"
+)
+
+// Теги релиза, наличие которых может быть обязательным.
+const (
+	TagTitle          = "title"
+	TagActors         = "actors"
+	TagYear           = "year"
+	TagLabel          = "label"
+	TagCatno          = "catno"
+	TagTracks         = "tracks"
+	TagTrackTitles    = "track_titles"
+	TagTrackPositions = "track_positions"
+)
+
+// DefaultMandatoryTags содержит обязательные теги релиза по умолчанию.
+var DefaultMandatoryTags = []string{
+	TagTitle, TagActors, TagYear, TagTracks, TagTrackTitles, TagTrackPositions}
+
+// ErrBadMandatoryTag возвращается для неизвестного обязательного тега.
+var ErrBadMandatoryTag = errors.New("unknown mandatory tag")
+
+// Проверки наличия тегов в релизе.
+var tagCheckers = map[string]func(*md.Release) bool{
+	TagTitle: func(r *md.Release) bool {
+		return r.Title != ""
+	},
+	TagActors: func(r *md.Release) bool {
+		return len(r.Actors) > 0 || len(r.ActorRoles) > 0
+	},
+	TagYear: func(r *md.Release) bool {
+		return r.Year != 0
+	},
+	TagLabel: func(r *md.Release) bool {
+		for _, pub := range r.Publishing {
+			if pub.Name != "" {
+				return true
+			}
+		}
+		return false
+	},
+	TagCatno: func(r *md.Release) bool {
+		for _, pub := range r.Publishing {
+			if pub.Catno != "" {
+				return true
+			}
+		}
+		return false
+	},
+	TagTracks: func(r *md.Release) bool {
+		return len(r.Tracks) > 0
+	},
+	TagTrackTitles: func(r *md.Release) bool {
+		for _, tr := range r.Tracks {
+			if tr.Title == "" {
+				return false
+			}
+		}
+		return true
+	},
+	TagTrackPositions: func(r *md.Release) bool {
+		for _, tr := range r.Tracks {
+			if tr.Position == "" {
+				return false
+			}
+		}
+		return true
+	},
+}
+
+// WithMandatoryTags задает список обязательных тегов релиза, по наличию которых
+// вычисляется статус Entry. По умолчанию используется DefaultMandatoryTags.
+func WithMandatoryTags(tags ...string) Option {
+	return func(m *Dbm) {
+		m.mandatoryTags = tags
+	}
+}
+
+// Проверяет, что все обязательные теги известны.
+func checkMandatoryTags(tags []string) error {
+	for _, tag := range tags {
+		if _, ok := tagCheckers[tag]; !ok {
+			return errors.Wrap(ErrBadMandatoryTag, tag)
+		}
+	}
+	return nil
+}
+
+// missingTags возвращает обязательные теги `tags`, отсутствующие в релизе `data`.
+// Пустые данные релиза соответствуют релизу без тегов.
+func missingTags(data []byte, tags []string) ([]string, error) {
+	release := md.NewRelease()
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, release); err != nil {
+			return nil, errors.Wrap(err, "release parsing failed")
+		}
+	}
+	var ret []string
+	for _, tag := range tags {
+		if !tagCheckers[tag](release) {
+			ret = append(ret, tag)
+		}
+	}
+	return ret, nil
+}
+
+// evalEntryStatus вычисляет статус Entry по наличию обязательных тегов и заполняет список
+// отсутствующих тегов `req.MissingTags`. Статус финализированного (по текущему статусу
+// `current`) Entry не изменяется.
+func (m *Dbm) evalEntryStatus(req *AudioDBRequest, current string) (err error) {
+	if req.MissingTags, err = missingTags(req.Entry.Json, m.mandatoryTags); err != nil {
+		return
+	}
+	if current == entity.StatusFinalyzed {
+		req.Entry.Status = current
+		return nil
+	}
+	if len(req.MissingTags) > 0 {
+		req.Entry.Status = entity.StatusWithoutMandatoryTags
+	} else {
+		req.Entry.Status = entity.StatusWithMandatoryTags
+	}
+	return nil
+}
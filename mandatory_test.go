@@ -0,0 +1,31 @@
+package dbm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMissingTags(t *testing.T) {
+	missing, err := missingTags(nil, DefaultMandatoryTags)
+	require.NoError(t, err)
+	assert.Equal(t, []string{TagTitle, TagActors, TagYear, TagTracks}, missing)
+
+	release := []byte(`{"title":"Remagine","year":2000,"actors":{"Sting":{}},
+		"publishing":[{"name":"A&M"}],
+		"tracks":[{"position":"1","title":"Intro"},{"position":"2"}]}`)
+	missing, err = missingTags(release, DefaultMandatoryTags)
+	require.NoError(t, err)
+	assert.Equal(t, []string{TagTrackTitles}, missing)
+
+	missing, err = missingTags(release, []string{TagTitle, TagLabel, TagCatno})
+	require.NoError(t, err)
+	assert.Equal(t, []string{TagCatno}, missing)
+
+	_, err = missingTags([]byte(`{"title":1}`), DefaultMandatoryTags)
+	assert.Error(t, err)
+
+	assert.NoError(t, checkMandatoryTags(DefaultMandatoryTags))
+	assert.ErrorIs(t, checkMandatoryTags([]string{"genre"}), ErrBadMandatoryTag)
+}
@@ -0,0 +1,157 @@
+package dbm
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// Политики слияния метаданных релиза предложения с метаданными каталога.
+const (
+	// MergeOverwrite заменяет релиз каталога релизом предложения.
+	MergeOverwrite = "overwrite"
+	// MergeFillMissing дополняет релиз каталога только отсутствующими в нем данными.
+	MergeFillMissing = "fill_missing"
+	// MergeKeepLocalTracks заменяет релиз каталога релизом предложения,
+	// но сохраняет локальные сведения о треках.
+	MergeKeepLocalTracks = "keep_local_tracks"
+)
+
+// ErrBadMergePolicy возвращается для неизвестной политики слияния.
+var ErrBadMergePolicy = errors.New("unknown merge policy")
+
+// mergeRelease объединяет JSON-представления релизов `md.Release` каталога и предложения
+// в соответствии с политикой `policy`.
+// В идентификаторы результирующего релиза добавляется ссылка на принятое предложение.
+func mergeRelease(local, suggested []byte, policy, extDB, extID string) ([]byte, error) {
+	dst, err := decodeJSONObject(local)
+	if err != nil {
+		return nil, errors.Wrap(err, "local release")
+	}
+	src, err := decodeJSONObject(suggested)
+	if err != nil {
+		return nil, errors.Wrap(err, "suggested release")
+	}
+
+	var ret map[string]interface{}
+	switch policy {
+	case MergeOverwrite:
+		ret = src
+	case MergeFillMissing:
+		ret = fillMissing(dst, src).(map[string]interface{})
+	case MergeKeepLocalTracks:
+		ret = src
+		for _, key := range []string{"tracks", "total_tracks"} {
+			if v, ok := dst[key]; ok && !isEmptyJSONValue(v) {
+				ret[key] = v
+			}
+		}
+	default:
+		return nil, errors.Wrap(ErrBadMergePolicy, policy)
+	}
+
+	ids, _ := ret["ids"].(map[string]interface{})
+	if ids == nil {
+		ids = map[string]interface{}{}
+		ret["ids"] = ids
+	}
+	ids[extDB] = extID
+
+	return json.Marshal(ret)
+}
+
+// Дополняет значение `dst` отсутствующими в нем данными из `src`.
+// Объекты объединяются рекурсивно, массивы одинаковой длины - поэлементно.
+func fillMissing(dst, src interface{}) interface{} {
+	if isEmptyJSONValue(dst) {
+		return src
+	}
+	switch d := dst.(type) {
+	case map[string]interface{}:
+		s, ok := src.(map[string]interface{})
+		if !ok {
+			return dst
+		}
+		for k, v := range s {
+			d[k] = fillMissing(d[k], v)
+		}
+	case []interface{}:
+		s, ok := src.([]interface{})
+		if !ok || len(s) != len(d) {
+			return dst
+		}
+		for i := range d {
+			d[i] = fillMissing(d[i], s[i])
+		}
+	}
+	return dst
+}
+
+func isEmptyJSONValue(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return true
+	case string:
+		return val == ""
+	case json.Number:
+		return val == "0"
+	case bool:
+		return !val
+	case []interface{}:
+		return len(val) == 0
+	case map[string]interface{}:
+		return len(val) == 0
+	}
+	return false
+}
+
+func decodeJSONObject(data []byte) (map[string]interface{}, error) {
+	ret := map[string]interface{}{}
+	if len(data) == 0 {
+		return ret, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&ret); err != nil {
+		return nil, err
+	}
+	if ret == nil { // JSON null
+		ret = map[string]interface{}{}
+	}
+	return ret, nil
+}
+
+// releaseActorNames возвращает имена всех акторов, упоминаемых в JSON релиза.
+func releaseActorNames(data []byte) (map[string]bool, error) {
+	release, err := decodeJSONObject(data)
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]bool{}
+	addKeys := func(v interface{}) {
+		if m, ok := v.(map[string]interface{}); ok {
+			for name := range m {
+				names[name] = true
+			}
+		}
+	}
+	addKeys(release["actors"])
+	addKeys(release["actors_roles"])
+	tracks, _ := release["tracks"].([]interface{})
+	for _, v := range tracks {
+		track, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		addKeys(track["actors"])
+		addKeys(track["actor_roles"])
+		for _, key := range []string{"record", "composition"} {
+			if sub, ok := track[key].(map[string]interface{}); ok {
+				addKeys(sub["actors"])
+				addKeys(sub["actor_roles"])
+			}
+		}
+	}
+	return names, nil
+}
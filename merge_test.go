@@ -0,0 +1,53 @@
+package dbm
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeRelease(t *testing.T) {
+	local := []byte(`{"title":"Remagine","year":0,"tracks":[{"position":"01","title":""}]}`)
+	suggested := []byte(
+		`{"title":"Remagine (Limited)","year":2005,"tracks":[{"position":"01","title":"Enter"}]}`)
+
+	data, err := mergeRelease(local, suggested, MergeFillMissing, "discogs", "720098")
+	require.NoError(t, err)
+	assert.JSONEq(t,
+		`{"title":"Remagine","year":2005,"tracks":[{"position":"01","title":"Enter"}],`+
+			`"ids":{"discogs":"720098"}}`,
+		string(data))
+
+	data, err = mergeRelease(local, suggested, MergeOverwrite, "discogs", "720098")
+	require.NoError(t, err)
+	assert.JSONEq(t,
+		`{"title":"Remagine (Limited)","year":2005,"tracks":[{"position":"01","title":"Enter"}],`+
+			`"ids":{"discogs":"720098"}}`,
+		string(data))
+
+	data, err = mergeRelease(local, suggested, MergeKeepLocalTracks, "discogs", "720098")
+	require.NoError(t, err)
+	assert.JSONEq(t,
+		`{"title":"Remagine (Limited)","year":2005,"tracks":[{"position":"01","title":""}],`+
+			`"ids":{"discogs":"720098"}}`,
+		string(data))
+
+	_, err = mergeRelease(local, suggested, "x", "discogs", "720098")
+	assert.ErrorIs(t, err, ErrBadMergePolicy)
+}
+
+func TestReleaseActorNames(t *testing.T) {
+	release := map[string]interface{}{
+		"actors_roles": map[string][]string{"After Forever": {"performer"}},
+		"tracks": []interface{}{
+			map[string]interface{}{
+				"record": map[string]interface{}{
+					"actor_roles": map[string][]string{"Floor Jansen": {"soprano vocals"}}}}}}
+	data, err := json.Marshal(release)
+	require.NoError(t, err)
+	names, err := releaseActorNames(data)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"After Forever": true, "Floor Jansen": true}, names)
+}
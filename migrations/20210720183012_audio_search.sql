@@ -0,0 +1,63 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+  'up SQL query';
+
+-- Полнотекстовый вектор релиза (md.Release) с весами:
+-- A - название релиза, B - названия треков, C - имена акторов,
+-- D - жанры, наименования лейблов и каталожные номера.
+CREATE FUNCTION audio.release_tsv(release JSONB) RETURNS tsvector AS $$
+SELECT
+    setweight(to_tsvector('simple', coalesce(release->>'title', '')), 'A') ||
+    setweight(jsonb_to_tsvector('simple',
+        jsonb_path_query_array(release, '$.tracks[*].title'),
+        '["string"]'), 'B') ||
+    setweight(jsonb_to_tsvector('simple',
+        jsonb_path_query_array(release, '$.actors.keyvalue().key') ||
+        jsonb_path_query_array(release, '$.actors_roles.keyvalue().key') ||
+        jsonb_path_query_array(release, '$.tracks[*].actors.keyvalue().key') ||
+        jsonb_path_query_array(release, '$.tracks[*].actor_roles.keyvalue().key') ||
+        jsonb_path_query_array(release, '$.tracks[*].record.actors.keyvalue().key') ||
+        jsonb_path_query_array(release, '$.tracks[*].record.actor_roles.keyvalue().key') ||
+        jsonb_path_query_array(release, '$.tracks[*].composition.actors.keyvalue().key') ||
+        jsonb_path_query_array(release, '$.tracks[*].composition.actor_roles.keyvalue().key'),
+        '["string"]'), 'C') ||
+    setweight(jsonb_to_tsvector('simple',
+        jsonb_path_query_array(release, '$.tracks[*].record.genres[*]') ||
+        jsonb_path_query_array(release, '$.publishing[*].name') ||
+        jsonb_path_query_array(release, '$.publishing[*].catno'),
+        '["string"]'), 'D')
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- Текстовое представление релиза для подсветки найденных фрагментов.
+CREATE FUNCTION audio.release_text(release JSONB) RETURNS TEXT AS $$
+SELECT concat_ws(' | ',
+    release->>'title',
+    (SELECT string_agg(v #>> '{}', ', ')
+        FROM jsonb_path_query(release, '$.tracks[*].title') v),
+    (SELECT string_agg(DISTINCT v #>> '{}', ', ')
+        FROM jsonb_array_elements(
+            jsonb_path_query_array(release, '$.tracks[*].actors.keyvalue().key') ||
+            jsonb_path_query_array(release, '$.tracks[*].actor_roles.keyvalue().key') ||
+            jsonb_path_query_array(release, '$.tracks[*].record.actors.keyvalue().key') ||
+            jsonb_path_query_array(release,
+                '$.tracks[*].record.actor_roles.keyvalue().key')) v),
+    (SELECT string_agg(DISTINCT v #>> '{}', ', ')
+        FROM jsonb_path_query(release, '$.tracks[*].record.genres[*]') v),
+    (SELECT string_agg(concat_ws(' ', v->>'name', v->>'catno'), ', ')
+        FROM jsonb_path_query(release, '$.publishing[*]') v))
+$$ LANGUAGE SQL IMMUTABLE;
+
+CREATE INDEX idx_albumentry_tsv ON audio.album_entry USING gin (audio.release_tsv(json));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+  'down SQL query';
+
+DROP INDEX audio.idx_albumentry_tsv;
+DROP FUNCTION audio.release_text(JSONB);
+DROP FUNCTION audio.release_tsv(JSONB);
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+  'up SQL query';
+
+CREATE TABLE audio.accepted_suggestion (
+	entry_id INTEGER PRIMARY KEY REFERENCES audio.album_entry (id),
+	ext_db audio.ext_db NOT NULL,
+	ext_id VARCHAR(32) NOT NULL,
+	merge_policy VARCHAR(20),
+	accepted_at TIMESTAMP NOT NULL
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+  'down SQL query';
+
+DROP TABLE audio.accepted_suggestion;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+  'up SQL query';
+
+CREATE TABLE audio.event_outbox (
+	id BIGSERIAL PRIMARY KEY,
+	type VARCHAR(20) NOT NULL,
+	entry_id INTEGER NOT NULL,
+	payload JSONB NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	published_at TIMESTAMP
+);
+CREATE INDEX idx_eventoutbox_pending ON audio.event_outbox (id) WHERE published_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+  'down SQL query';
+
+DROP TABLE audio.event_outbox;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+  'up SQL query';
+
+CREATE TABLE audio.album_entry_revision (
+	id SERIAL PRIMARY KEY,
+	entry_id INTEGER REFERENCES audio.album_entry (id),
+	path VARCHAR(255) NOT NULL,
+	json JSONB,
+	status audio.entry_status,
+	last_modified TIMESTAMP NOT NULL,
+	actors JSONB,
+	accepted JSONB,
+	cmd VARCHAR(32) NOT NULL,
+	created_at TIMESTAMP NOT NULL
+);
+CREATE INDEX idx_albumentryrevision_entry ON audio.album_entry_revision (entry_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+  'down SQL query';
+
+DROP TABLE audio.album_entry_revision;
+-- +goose StatementEnd
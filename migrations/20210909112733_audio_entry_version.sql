@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+  'up SQL query';
+
+ALTER TABLE audio.album_entry ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+  'down SQL query';
+
+ALTER TABLE audio.album_entry DROP COLUMN version;
+-- +goose StatementEnd
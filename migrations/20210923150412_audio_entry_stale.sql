@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+  'up SQL query';
+
+ALTER TABLE audio.album_entry ADD COLUMN stale_since TIMESTAMP;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+  'down SQL query';
+
+ALTER TABLE audio.album_entry DROP COLUMN stale_since;
+-- +goose StatementEnd
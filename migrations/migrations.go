@@ -0,0 +1,108 @@
+// Package migrations содержит миграции схемы БД audio и средства их применения.
+// Миграции совместимы с утилитой goose: используется формат файлов goose и таблица
+// версий goose_db_version.
+package migrations
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// ErrBadMigration возвращается для файла миграции, не соответствующего формату goose.
+var ErrBadMigration = errors.New("malformed migration")
+
+// Migration описывает миграцию схемы БД.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Load возвращает встроенные миграции, упорядоченные по версии.
+func Load() ([]*Migration, error) {
+	names, err := files.ReadDir(".")
+	if err != nil {
+		return nil, errors.Wrap(err, "migrations reading failed")
+	}
+	var ret []*Migration
+	for _, entry := range names {
+		data, err := files.ReadFile(entry.Name())
+		if err != nil {
+			return nil, errors.Wrapf(err, "migration reading failed: %s", entry.Name())
+		}
+		m, err := parse(entry.Name(), data)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, m)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Version < ret[j].Version })
+	return ret, nil
+}
+
+// LatestVersion возвращает версию последней встроенной миграции, т.е. версию схемы БД,
+// ожидаемую сервисом.
+func LatestVersion() (int64, error) {
+	all, err := Load()
+	if err != nil {
+		return 0, err
+	}
+	if len(all) == 0 {
+		return 0, nil
+	}
+	return all[len(all)-1].Version, nil
+}
+
+// Разбирает файл миграции `<версия>_<наименование>.sql`.
+func parse(filename string, data []byte) (*Migration, error) {
+	base := strings.TrimSuffix(path.Base(filename), ".sql")
+	i := strings.IndexByte(base, '_')
+	if i <= 0 {
+		return nil, errors.Wrapf(ErrBadMigration, "%s: no version in file name", filename)
+	}
+	version, err := strconv.ParseInt(base[:i], 10, 64)
+	if err != nil {
+		return nil, errors.Wrapf(ErrBadMigration, "%s: bad version in file name", filename)
+	}
+	m := &Migration{Version: version, Name: base[i+1:]}
+
+	var up, down strings.Builder
+	var section *strings.Builder
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch strings.TrimSpace(line) {
+		case "-- +goose Up":
+			section = &up
+			continue
+		case "-- +goose Down":
+			section = &down
+			continue
+		case "-- +goose StatementBegin", "-- +goose StatementEnd":
+			continue
+		}
+		if section != nil {
+			section.WriteString(line)
+			section.WriteByte('\n')
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "migration reading failed: %s", filename)
+	}
+	m.Up, m.Down = strings.TrimSpace(up.String()), strings.TrimSpace(down.String())
+	if m.Up == "" {
+		return nil, errors.Wrapf(ErrBadMigration, "%s: no Up section", filename)
+	}
+	return m, nil
+}
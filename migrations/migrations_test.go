@@ -0,0 +1,61 @@
+package migrations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	all, err := Load()
+	require.NoError(t, err)
+	require.NotEmpty(t, all)
+	assert.Equal(t, int64(20210526143944), all[0].Version)
+	assert.Equal(t, "audio_types", all[0].Name)
+	for i, m := range all {
+		assert.NotEmpty(t, m.Up, m.Name)
+		assert.NotEmpty(t, m.Down, m.Name)
+		assert.NotContains(t, m.Up, "+goose", m.Name)
+		if i > 0 {
+			assert.Less(t, all[i-1].Version, m.Version)
+		}
+	}
+
+	latest, err := LatestVersion()
+	require.NoError(t, err)
+	assert.Equal(t, all[len(all)-1].Version, latest)
+}
+
+func TestParse(t *testing.T) {
+	m, err := parse("20210909112733_audio_entry_version.sql", []byte(`-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE audio.album_entry ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+ALTER TABLE audio.album_entry DROP COLUMN version;
+`))
+	require.NoError(t, err)
+	assert.Equal(t, &Migration{
+		Version: 20210909112733,
+		Name:    "audio_entry_version",
+		Up:      "ALTER TABLE audio.album_entry ADD COLUMN version INTEGER NOT NULL DEFAULT 1;",
+		Down:    "ALTER TABLE audio.album_entry DROP COLUMN version;"}, m)
+
+	_, err = parse("audio.sql", []byte("-- +goose Up\nSELECT 1;"))
+	assert.ErrorIs(t, err, ErrBadMigration)
+	_, err = parse("20210909112733_empty.sql", []byte("-- +goose Down\nSELECT 1;"))
+	assert.ErrorIs(t, err, ErrBadMigration)
+}
+
+func TestCurrentVersion(t *testing.T) {
+	assert.Equal(t, int64(0), currentVersion(nil))
+	assert.Equal(t, int64(3), currentVersion([]versionRecord{
+		{version: 4, applied: false},
+		{version: 4, applied: true},
+		{version: 3, applied: true},
+		{version: 1, applied: true},
+		{version: 0, applied: true},
+	}))
+}
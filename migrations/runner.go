@@ -0,0 +1,190 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// SchemaName содержит наименование схемы БД сервиса.
+const SchemaName = "audio"
+
+// Таблица версий схемы БД в формате goose.
+const versionTable = "goose_db_version"
+
+// Идентификатор рекомендательной блокировки, исключающей одновременное применение
+// миграций несколькими экземплярами сервиса.
+const migrationLockID = 0x617564696f // "audio"
+
+// Ошибки несоответствия версии схемы БД версии, ожидаемой сервисом.
+var (
+	ErrSchemaTooOld = errors.New("database schema is older than expected")
+	ErrSchemaTooNew = errors.New("database schema is newer than expected")
+)
+
+// Version возвращает текущую версию схемы БД (0 для БД без миграций).
+func Version(ctx context.Context, pool *pgxpool.Pool) (int64, error) {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "schema version reading failed")
+	}
+	defer conn.Release()
+	return version(ctx, conn.Conn())
+}
+
+// Check проверяет соответствие версии схемы БД последней встроенной миграции.
+func Check(ctx context.Context, pool *pgxpool.Pool) error {
+	current, err := Version(ctx, pool)
+	if err != nil {
+		return err
+	}
+	expected, err := LatestVersion()
+	if err != nil {
+		return err
+	}
+	switch {
+	case current < expected:
+		return errors.Wrapf(ErrSchemaTooOld, "version %d, expected %d", current, expected)
+	case current > expected:
+		return errors.Wrapf(ErrSchemaTooNew, "version %d, expected %d", current, expected)
+	}
+	return nil
+}
+
+// Up создает схему БД при ее отсутствии и применяет миграции, версии которых больше
+// текущей версии схемы. Каждая миграция применяется в отдельной транзакции.
+// Возвращает версии примененных миграций.
+func Up(ctx context.Context, pool *pgxpool.Pool) (applied []int64, err error) {
+	all, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "migration failed")
+	}
+	defer conn.Release()
+
+	if _, err = conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return nil, errors.Wrap(err, "migration lock failed")
+	}
+	defer conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockID)
+
+	if _, err = conn.Exec(ctx, "CREATE SCHEMA IF NOT EXISTS "+SchemaName); err != nil {
+		return nil, errors.Wrap(err, "schema creation failed")
+	}
+	if err = ensureVersionTable(ctx, conn.Conn()); err != nil {
+		return nil, err
+	}
+	current, err := version(ctx, conn.Conn())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range all {
+		if m.Version <= current {
+			continue
+		}
+		if err = apply(ctx, conn.Conn(), m); err != nil {
+			return applied, err
+		}
+		applied = append(applied, m.Version)
+	}
+	return applied, nil
+}
+
+// Применяет миграцию и сохраняет ее версию в одной транзакции.
+func apply(ctx context.Context, conn *pgx.Conn, m *Migration) (err error) {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "migration %d_%s failed", m.Version, m.Name)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+			err = errors.Wrapf(err, "migration %d_%s failed", m.Version, m.Name)
+		}
+	}()
+	if _, err = tx.Exec(ctx, m.Up); err != nil {
+		return
+	}
+	_, err = tx.Exec(
+		ctx, "INSERT INTO "+versionTable+" (version_id,is_applied) VALUES ($1,true)", m.Version)
+	if err != nil {
+		return
+	}
+	return tx.Commit(ctx)
+}
+
+// Создает таблицу версий схемы БД в формате goose при ее отсутствии.
+func ensureVersionTable(ctx context.Context, conn *pgx.Conn) error {
+	var exists bool
+	err := conn.QueryRow(ctx, "SELECT to_regclass($1) IS NOT NULL", versionTable).Scan(&exists)
+	if err != nil || exists {
+		return errors.Wrap(err, "version table check failed")
+	}
+	_, err = conn.Exec(ctx, `CREATE TABLE `+versionTable+` (
+		id serial NOT NULL,
+		version_id bigint NOT NULL,
+		is_applied boolean NOT NULL,
+		tstamp timestamp NULL default now(),
+		PRIMARY KEY(id)
+	);
+	INSERT INTO `+versionTable+` (version_id,is_applied) VALUES (0,true);`)
+	return errors.Wrap(err, "version table creation failed")
+}
+
+// Возвращает текущую версию схемы БД по таблице версий.
+func version(ctx context.Context, conn *pgx.Conn) (int64, error) {
+	var exists bool
+	err := conn.QueryRow(ctx, "SELECT to_regclass($1) IS NOT NULL", versionTable).Scan(&exists)
+	if err != nil || !exists {
+		return 0, errors.Wrap(err, "schema version reading failed")
+	}
+
+	rows, err := conn.Query(
+		ctx, "SELECT version_id,is_applied FROM "+versionTable+" ORDER BY id DESC")
+	if err != nil {
+		return 0, errors.Wrap(err, "schema version reading failed")
+	}
+	defer rows.Close()
+	var records []versionRecord
+	for rows.Next() {
+		var r versionRecord
+		if err = rows.Scan(&r.version, &r.applied); err != nil {
+			return 0, errors.Wrap(err, "schema version reading failed")
+		}
+		records = append(records, r)
+	}
+	if err = rows.Err(); err != nil {
+		return 0, errors.Wrap(err, "schema version reading failed")
+	}
+	return currentVersion(records), nil
+}
+
+// Запись таблицы версий схемы БД.
+type versionRecord struct {
+	version int64
+	applied bool
+}
+
+// currentVersion вычисляет текущую версию схемы БД по записям таблицы версий,
+// упорядоченным от последней к первой: наибольшая версия, последняя запись которой
+// соответствует применению миграции (а не ее откату).
+func currentVersion(records []versionRecord) int64 {
+	var ret int64
+	seen := map[int64]bool{}
+	for _, r := range records {
+		if seen[r.version] {
+			continue
+		}
+		seen[r.version] = true
+		if r.applied && r.version > ret {
+			ret = r.version
+		}
+	}
+	return ret
+}
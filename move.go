@@ -0,0 +1,120 @@
+package dbm
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+)
+
+// Проверяет и нормализует корни дерева каталогов команды `move_tree`.
+func treePrefixes(oldPrefix, newPrefix string) (string, string, error) {
+	oldPrefix, newPrefix = strings.TrimSuffix(oldPrefix, "/"), strings.TrimSuffix(newPrefix, "/")
+	switch {
+	case oldPrefix == "" || newPrefix == "":
+		return "", "", errors.Wrap(ErrBadRequest, "old_prefix and new_prefix are required")
+	case oldPrefix == newPrefix:
+		return "", "", errors.Wrap(ErrBadRequest, "old_prefix and new_prefix are equal")
+	case strings.HasPrefix(newPrefix, oldPrefix+"/"), strings.HasPrefix(oldPrefix, newPrefix+"/"):
+		return "", "", errors.Wrap(ErrBadRequest, "old_prefix and new_prefix are nested")
+	}
+	return oldPrefix, newPrefix, nil
+}
+
+// Формирует список перемещений записей дерева каталогов.
+func treeMoves(entries []*entity.AlbumEntry, oldPrefix, newPrefix string) []*entity.EntryMove {
+	ret := make([]*entity.EntryMove, 0, len(entries))
+	for _, ent := range entries {
+		ret = append(ret, &entity.EntryMove{
+			EntryID: ent.ID,
+			OldPath: ent.Path,
+			NewPath: newPrefix + strings.TrimPrefix(ent.Path, oldPrefix)})
+	}
+	return ret
+}
+
+// moveTree переносит все каталоги дерева с корнем `req.OldPrefix` в `req.NewPrefix` в одной
+// транзакции. Список перемещений возвращается в поле `moves`, а пути, уже занятые другими
+// каталогами, - в поле `collisions`. При наличии коллизий перемещение не выполняется.
+// В режиме `req.DryRun` изменения не сохраняются. Дерево, содержащее финализированные
+// каталоги, не перемещается.
+func (m *Dbm) moveTree(req *AudioDBRequest) (_ []byte, err error) {
+	req.Moves, req.Collisions, err = m.applyMoveTree(
+		m.ctx, req.Cmd, req.OldPrefix, req.NewPrefix, req.DryRun)
+	if err != nil {
+		return
+	}
+	return json.Marshal(req)
+}
+
+// Переносит дерево каталогов с корнем `oldPrefix` в `newPrefix` и возвращает список
+// перемещений и пути, уже занятые другими каталогами.
+func (m *Dbm) applyMoveTree(ctx context.Context, cmd, oldPrefix, newPrefix string, dryRun bool) (
+	moves []*entity.EntryMove, collisions []string, err error) {
+
+	if oldPrefix, newPrefix, err = treePrefixes(oldPrefix, newPrefix); err != nil {
+		return
+	}
+
+	txctx, tx, err := m.begin(ctx)
+	if err != nil {
+		return
+	}
+	defer m.completeTx(tx, &err)
+
+	entries, err := m.store.TreeEntries(txctx, oldPrefix)
+	if err != nil {
+		return
+	}
+	// перемещение, обнаруженное отслеживанием, уже выполнено в файловой системе,
+	// поэтому пути финализированных каталогов также приводятся в соответствие с ней
+	if cmd != watcherCmd {
+		for _, ent := range entries {
+			if err = checkNotFinalyzed(ent); err != nil {
+				return
+			}
+		}
+	}
+	moves = treeMoves(entries, oldPrefix, newPrefix)
+	newPaths := make([]string, 0, len(moves))
+	for _, mv := range moves {
+		newPaths = append(newPaths, mv.NewPath)
+	}
+	if collisions, err = m.store.ExistingPaths(txctx, newPaths); err != nil {
+		return
+	}
+	if len(collisions) > 0 && !dryRun {
+		err = errors.Wrapf(ErrPathExists, "%d path collisions", len(collisions))
+		return
+	}
+	if dryRun || len(entries) == 0 {
+		return
+	}
+
+	for _, ent := range entries {
+		if err = m.store.SaveEntryRevision(txctx, ent.ID, cmd); err != nil {
+			return
+		}
+	}
+	if _, err = m.store.MoveTree(txctx, oldPrefix, newPrefix); err != nil {
+		return
+	}
+	for i, ent := range entries {
+		err = m.recordEvent(txctx, &entity.EntryEvent{
+			Type:      entity.EntryRenamed,
+			Cmd:       cmd,
+			EntryID:   ent.ID,
+			Path:      moves[i].NewPath,
+			OldPath:   ent.Path,
+			OldStatus: ent.Status,
+			NewStatus: ent.Status,
+			Changed:   []string{"path"}})
+		if err != nil {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,66 @@
+package dbm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+)
+
+func TestTreeMoves(t *testing.T) {
+	oldPrefix, newPrefix, err := treePrefixes("Rock/Pink Floyd/", "Pink Floyd")
+	require.NoError(t, err)
+	assert.Equal(t, "Rock/Pink Floyd", oldPrefix)
+	assert.Equal(t, "Pink Floyd", newPrefix)
+
+	moves := treeMoves([]*entity.AlbumEntry{
+		{ID: 1, Path: "Rock/Pink Floyd"},
+		{ID: 2, Path: "Rock/Pink Floyd/1973 - The Dark Side Of The Moon"},
+	}, oldPrefix, newPrefix)
+	assert.Equal(t, []*entity.EntryMove{
+		{EntryID: 1, OldPath: "Rock/Pink Floyd", NewPath: "Pink Floyd"},
+		{EntryID: 2, OldPath: "Rock/Pink Floyd/1973 - The Dark Side Of The Moon",
+			NewPath: "Pink Floyd/1973 - The Dark Side Of The Moon"},
+	}, moves)
+
+	for _, prefixes := range [][2]string{{"", "a"}, {"a", "a/"}, {"a", "a/b"}, {"a/b", "a"}} {
+		_, _, err = treePrefixes(prefixes[0], prefixes[1])
+		assert.ErrorIs(t, err, ErrBadRequest, prefixes)
+	}
+}
+
+func TestMoveFinalyzed(t *testing.T) {
+	root := t.TempDir()
+	m := New("", WithStore(entity.NewMemStore()), WithLibraryRoot(root))
+	defer m.Close()
+	for _, path := range []string{"Rock/A", "Rock/A/B"} {
+		executeCmd(t, m, NewAudioDBRequest("set_entry", &entity.AlbumEntry{Path: path}))
+	}
+	executeCmd(t, m, NewAudioDBRequest("finalyze_entry", &entity.AlbumEntry{Path: "Rock/A/B"}))
+	pathOf := func(id int) string {
+		return executeCmd(t, m, NewAudioDBRequest("get_entry", &entity.AlbumEntry{ID: id})).Entry.Path
+	}
+	id := executeCmd(t, m, NewAudioDBRequest("get_entry", &entity.AlbumEntry{Path: "Rock/A/B"})).Entry.ID
+
+	// команды не изменяют пути финализированных каталогов
+	req := NewAudioDBRequest("rename_entry", &entity.AlbumEntry{ID: id})
+	req.NewPath = "Rock/A/C"
+	_, err := m.Execute(req)
+	assert.ErrorIs(t, err, ErrAlreadyFinalyzed)
+	req = NewAudioDBRequest("move_tree", nil)
+	req.OldPrefix, req.NewPrefix = "Rock", "Metal"
+	_, err = m.Execute(req)
+	assert.ErrorIs(t, err, ErrAlreadyFinalyzed)
+	assert.Equal(t, "Rock/A/B", pathOf(id))
+	_, err = m.Execute(NewAudioDBRequest("get_entry", &entity.AlbumEntry{Path: "Rock/A"}))
+	assert.NoError(t, err)
+
+	// перемещение, обнаруженное в файловой системе, применяется к финализированным каталогам
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "Metal", "A", "B"), 0755))
+	m.applyDirChanges(m.ctx, []dirMove{{from: "Rock", to: "Metal"}}, nil)
+	assert.Equal(t, "Metal/A/B", pathOf(id))
+}
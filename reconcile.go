@@ -0,0 +1,330 @@
+package dbm
+
+import (
+	"context"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+)
+
+// Действия с записями каталогов, не найденных в файловой системе.
+const (
+	OrphanReport    = "report"
+	OrphanMarkStale = "mark_stale"
+	OrphanDelete    = "delete"
+)
+
+// DefaultMaxOrphanRatio задает по умолчанию наибольшую долю записей, каталоги которых
+// не найдены, при которой выполняются действия OrphanMarkStale и OrphanDelete.
+const DefaultMaxOrphanRatio = 0.5
+
+// Ошибки сверки с файловой системой.
+var (
+	ErrNoLibraryRoot   = errors.Wrap(ErrBadRequest, "library root is not configured")
+	ErrBadOrphanAction = errors.Wrap(ErrBadRequest, "unknown orphan action")
+	ErrTooManyOrphans  = errors.Wrap(ErrBadRequest, "too many orphan entries")
+)
+
+// Расширения файлов, по наличию которых каталог считается каталогом альбома.
+var audioExts = map[string]bool{
+	".aac": true, ".aif": true, ".aiff": true, ".ape": true, ".dff": true, ".dsf": true,
+	".flac": true, ".m4a": true, ".mp3": true, ".ogg": true, ".opus": true, ".wav": true,
+	".wv": true,
+}
+
+// ReconcileReport описывает результат сверки БД с файловой системой.
+type ReconcileReport struct {
+	// каталоги альбомов, отсутствующие в audio.album_entry
+	Missing []string `json:"missing,omitempty"`
+	// записи, каталоги которых не найдены
+	Orphans []*entity.AlbumEntry `json:"orphans,omitempty"`
+	// записи, каталоги которых изменены позже last_modified
+	Modified []*entity.AlbumEntry `json:"modified,omitempty"`
+	// записи с отметкой stale_since, каталоги которых снова найдены
+	Restored     []*entity.AlbumEntry `json:"restored,omitempty"`
+	OrphanAction string               `json:"orphan_action"`
+	StartedAt    time.Time            `json:"started_at"`
+	FinishedAt   time.Time            `json:"finished_at"`
+}
+
+// WithLibraryRoot задает корневой каталог аудио-библиотеки, с которым сверяются пути Entry.
+func WithLibraryRoot(root string) Option {
+	return func(m *Dbm) {
+		m.libraryRoot = root
+	}
+}
+
+// WithReconcilePeriod включает периодическую сверку БД с файловой системой.
+func WithReconcilePeriod(d time.Duration) Option {
+	return func(m *Dbm) {
+		if d > 0 {
+			m.reconcilePeriod = d
+		}
+	}
+}
+
+// WithOrphanAction задает действие по умолчанию с записями каталогов, не найденных
+// в файловой системе: OrphanReport (по умолчанию), OrphanMarkStale или OrphanDelete.
+func WithOrphanAction(action string) Option {
+	return func(m *Dbm) {
+		m.orphanAction = action
+	}
+}
+
+// WithMaxOrphanRatio задает наибольшую долю записей (от 0 до 1), каталоги которых не найдены,
+// при которой выполняются действия OrphanMarkStale и OrphanDelete (DefaultMaxOrphanRatio
+// по умолчанию). При большей доле, а также при отсутствии в библиотеке каталогов альбомов
+// (например, если файловая система библиотеки не смонтирована) сверка завершается ошибкой
+// ErrTooManyOrphans без изменения данных. Значение 1 отключает проверку доли.
+func WithMaxOrphanRatio(ratio float64) Option {
+	return func(m *Dbm) {
+		if ratio > 0 && ratio <= 1 {
+			m.maxOrphanRatio = ratio
+		}
+	}
+}
+
+// reconcile сверяет БД с файловой системой. Действие с записями ненайденных каталогов
+// задается в `req.OrphanAction` или опцией сервиса WithOrphanAction.
+func (m *Dbm) reconcile(req *AudioDBRequest) (_ []byte, err error) {
+	if req.OrphanAction == "" {
+		req.OrphanAction = m.orphanAction
+	}
+	if req.Reconcile, err = m.reconcileLibrary(m.ctx, req.Cmd, req.OrphanAction); err != nil {
+		return
+	}
+	return json.Marshal(req)
+}
+
+// Выполняет сверку БД с файловой системой.
+// Одновременно выполняется не более одной сверки.
+func (m *Dbm) reconcileLibrary(
+	ctx context.Context, cmd, action string) (report *ReconcileReport, err error) {
+
+	switch action {
+	case OrphanReport, OrphanMarkStale, OrphanDelete:
+	default:
+		return nil, errors.Wrap(ErrBadOrphanAction, action)
+	}
+	if m.libraryRoot == "" {
+		return nil, ErrNoLibraryRoot
+	}
+
+	m.reconcileMu.Lock()
+	defer m.reconcileMu.Unlock()
+
+	startedAt := time.Now().UTC()
+	folders, err := scanLibrary(m.libraryRoot)
+	if err != nil {
+		return
+	}
+
+	txctx, tx, err := m.begin(ctx)
+	if err != nil {
+		return
+	}
+	defer m.completeTx(tx, &err)
+
+	entries, err := m.store.LibraryEntries(txctx)
+	if err != nil {
+		return
+	}
+	report = compareLibrary(folders, entries, func(path string) bool {
+		_, err := os.Stat(filepath.Join(m.libraryRoot, filepath.FromSlash(path)))
+		return !os.IsNotExist(err)
+	})
+	report.OrphanAction, report.StartedAt = action, startedAt
+	if err = m.checkOrphans(action, len(folders), len(entries), len(report.Orphans)); err != nil {
+		return nil, err
+	}
+
+	if len(report.Restored) > 0 {
+		if err = m.store.MarkEntriesStale(txctx, entryIDs(report.Restored), time.Time{}); err != nil {
+			return
+		}
+	}
+	if err = m.applyOrphanAction(txctx, cmd, action, report.Orphans, startedAt); err != nil {
+		return
+	}
+
+	report.FinishedAt = time.Now().UTC()
+	return report, nil
+}
+
+// Проверяет, что действие `action` с `orphans` записями из `entries` не вызвано
+// недоступностью библиотеки из `folders` каталогов альбомов.
+func (m *Dbm) checkOrphans(action string, folders, entries, orphans int) error {
+	if action == OrphanReport || orphans == 0 {
+		return nil
+	}
+	if folders == 0 || float64(orphans) > m.maxOrphanRatio*float64(entries) {
+		return errors.Wrapf(ErrTooManyOrphans,
+			"%d of %d entries, %d album folders, %s is refused", orphans, entries, folders, action)
+	}
+	return nil
+}
+
+// Выполняет действие `action` с записями `orphans`, каталоги которых не найдены.
+func (m *Dbm) applyOrphanAction(ctx context.Context,
+	cmd, action string, orphans []*entity.AlbumEntry, now time.Time) (err error) {
+
+	switch action {
+	case OrphanMarkStale:
+		var ids []int
+		for _, ent := range orphans {
+			if ent.StaleSince == nil {
+				ids = append(ids, ent.ID)
+				ent.StaleSince = &now
+			}
+		}
+		if len(ids) > 0 {
+			err = m.store.MarkEntriesStale(ctx, ids, now)
+		}
+	case OrphanDelete:
+		for _, ent := range orphans {
+			if err = m.deleteEntryData(ctx, ent); err != nil {
+				return
+			}
+			err = m.recordEvent(ctx, &entity.EntryEvent{
+				Type:      entity.EntryDeleted,
+				Cmd:       cmd,
+				EntryID:   ent.ID,
+				Path:      ent.Path,
+				OldStatus: ent.Status})
+			if err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// Запускает периодическую сверку БД с файловой системой.
+func (m *Dbm) startReconcileJob() {
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.stopReconcile = cancel
+	m.reconcileDone = make(chan struct{})
+
+	go func() {
+		defer close(m.reconcileDone)
+		ticker := time.NewTicker(m.reconcilePeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			// сверка не выполняется одновременно с командами сервиса
+			var report *ReconcileReport
+			var err error
+			m.disp.Do(exclusiveKey, func() {
+				if err = ctx.Err(); err == nil {
+					report, err = m.reconcileLibrary(ctx, "reconcile", m.orphanAction)
+				}
+			})
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				m.LogOnErrorWithContext(err, "Reconcile job")
+				continue
+			}
+			m.Log.Infof("reconcile: %d missing, %d orphans (%s), %d modified, %d restored",
+				len(report.Missing), len(report.Orphans), report.OrphanAction,
+				len(report.Modified), len(report.Restored))
+		}
+	}()
+}
+
+// scanLibrary возвращает каталоги альбомов библиотеки `root` (пути относительно `root`
+// с разделителем "/") и время их последнего изменения: наибольшее из времени изменения
+// каталога и его аудио-файлов.
+func scanLibrary(root string) (map[string]time.Time, error) {
+	ret := map[string]time.Time{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !audioExts[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+		dir := filepath.Dir(path)
+		rel, err := filepath.Rel(root, dir)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		mtime, ok := ret[rel]
+		if !ok {
+			dirInfo, err := os.Stat(dir)
+			if err != nil {
+				return err
+			}
+			mtime = dirInfo.ModTime()
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(mtime) {
+			mtime = info.ModTime()
+		}
+		ret[rel] = mtime
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "library scanning failed")
+	}
+	return ret, nil
+}
+
+// compareLibrary сопоставляет каталоги альбомов `folders` записям `entries`.
+// Запись считается потерянной, если ее каталог не является каталогом альбома и не
+// существует (`exists`).
+func compareLibrary(
+	folders map[string]time.Time,
+	entries []*entity.AlbumEntry,
+	exists func(path string) bool) *ReconcileReport {
+
+	report := &ReconcileReport{}
+	known := make(map[string]bool, len(entries))
+	for _, ent := range entries {
+		known[ent.Path] = true
+		mtime, ok := folders[ent.Path]
+		if !ok && !exists(ent.Path) {
+			report.Orphans = append(report.Orphans, ent)
+			continue
+		}
+		if ent.StaleSince != nil {
+			report.Restored = append(report.Restored, ent)
+		}
+		// время в БД хранится с точностью до микросекунд
+		if ok && mtime.UTC().Truncate(time.Microsecond).After(ent.LastModified) {
+			report.Modified = append(report.Modified, ent)
+		}
+	}
+	for path := range folders {
+		if !known[path] {
+			report.Missing = append(report.Missing, path)
+		}
+	}
+	sort.Strings(report.Missing)
+	return report
+}
+
+func entryIDs(entries []*entity.AlbumEntry) []int {
+	ret := make([]int, 0, len(entries))
+	for _, ent := range entries {
+		ret = append(ret, ent.ID)
+	}
+	return ret
+}
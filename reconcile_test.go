@@ -0,0 +1,103 @@
+package dbm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+)
+
+func TestScanLibrary(t *testing.T) {
+	root := t.TempDir()
+	album := filepath.Join(root, "Pink Floyd", "1973 - The Dark Side Of The Moon")
+	require.NoError(t, os.MkdirAll(album, 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "Pink Floyd", "Scans"), 0755))
+	for _, fn := range []string{
+		filepath.Join(album, "01 - Speak To Me.flac"),
+		filepath.Join(album, "cover.jpg"),
+		filepath.Join(root, "Pink Floyd", "Scans", "back.jpg"),
+	} {
+		require.NoError(t, ioutil.WriteFile(fn, nil, 0644))
+	}
+	mtime := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, os.Chtimes(filepath.Join(album, "01 - Speak To Me.flac"), mtime, mtime))
+
+	folders, err := scanLibrary(root)
+	require.NoError(t, err)
+	assert.Equal(t,
+		map[string]time.Time{"Pink Floyd/1973 - The Dark Side Of The Moon": mtime},
+		toUTC(folders))
+}
+
+func TestCompareLibrary(t *testing.T) {
+	lastModified := time.Date(2021, 6, 4, 13, 55, 59, 0, time.UTC)
+	stale := lastModified.Add(time.Hour)
+	entries := []*entity.AlbumEntry{
+		{ID: 1, Path: "actual", LastModified: lastModified},
+		{ID: 2, Path: "modified", LastModified: lastModified},
+		{ID: 3, Path: "orphan", LastModified: lastModified},
+		{ID: 4, Path: "restored", LastModified: lastModified, StaleSince: &stale},
+		{ID: 5, Path: "without_audio", LastModified: lastModified},
+	}
+	folders := map[string]time.Time{
+		"actual":   lastModified.Add(time.Nanosecond),
+		"modified": lastModified.Add(time.Second),
+		"restored": lastModified,
+		"new":      lastModified,
+	}
+	report := compareLibrary(folders, entries, func(path string) bool {
+		return path == "without_audio"
+	})
+	assert.Equal(t, []string{"new"}, report.Missing)
+	assert.Equal(t, []int{3}, entryIDs(report.Orphans))
+	assert.Equal(t, []int{2}, entryIDs(report.Modified))
+	assert.Equal(t, []int{4}, entryIDs(report.Restored))
+}
+
+func toUTC(folders map[string]time.Time) map[string]time.Time {
+	for path, mtime := range folders {
+		folders[path] = mtime.UTC()
+	}
+	return folders
+}
+
+func TestReconcileRefusesUnsafeOrphanAction(t *testing.T) {
+	root := t.TempDir()
+	m := New("", WithStore(entity.NewMemStore()), WithLibraryRoot(root))
+	defer m.Close()
+	for _, path := range []string{"a", "b", "c"} {
+		executeCmd(t, m, NewAudioDBRequest("set_entry", &entity.AlbumEntry{Path: path}))
+	}
+	countEntries := func() int {
+		return len(executeCmd(t, m, NewAudioDBRequest("list_entries", nil)).Entries)
+	}
+
+	// пустой корень библиотеки (например, несмонтированный) не приводит к удалению записей
+	for _, action := range []string{OrphanDelete, OrphanMarkStale} {
+		_, err := m.reconcileLibrary(m.ctx, "reconcile", action)
+		assert.ErrorIs(t, err, ErrTooManyOrphans, action)
+	}
+	assert.Equal(t, 3, countEntries())
+	report, err := m.reconcileLibrary(m.ctx, "reconcile", OrphanReport)
+	require.NoError(t, err)
+	assert.Len(t, report.Orphans, 3)
+
+	// доля потерянных записей ограничена
+	require.NoError(t, os.Mkdir(filepath.Join(root, "a"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(root, "a", "01.flac"), nil, 0644))
+	_, err = m.reconcileLibrary(m.ctx, "reconcile", OrphanDelete)
+	assert.ErrorIs(t, err, ErrTooManyOrphans)
+	assert.Equal(t, 3, countEntries())
+
+	WithMaxOrphanRatio(1)(m)
+	report, err = m.reconcileLibrary(m.ctx, "reconcile", OrphanDelete)
+	require.NoError(t, err)
+	assert.Len(t, report.Orphans, 2)
+	assert.Equal(t, 1, countEntries())
+}
@@ -0,0 +1,277 @@
+package dbm
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+)
+
+// Client вызывает команды сервиса через транспорт `Transport` (брокер сообщений,
+// сервис в том же процессе и т.д.).
+// В отличие от AudioDBResponse.Unwrap() ошибки выполнения команд возвращаются
+// вызывающему коду в виде *ResponseError, совместимой с ErrNotFound, ErrConflict и т.д.
+type Client struct {
+	tr       Transport
+	retries  int
+	backoff  time.Duration
+	retryAll bool
+}
+
+// Команды, не изменяющие данные: их повтор безопасен, даже если сервис выполнил
+// предыдущую попытку, но ответ на нее не был получен.
+var idempotentCmds = map[string]bool{
+	"ping":                 true,
+	"get_entry":            true,
+	"list_entries":         true,
+	"search_entries":       true,
+	"get_entry_history":    true,
+	"diff_entry_revisions": true,
+}
+
+// Команды, проверяющие версию каталога из запроса: повторная попытка команды с ненулевой
+// версией после потерянного ответа завершается конфликтом версий, а не повторным изменением.
+// Команда `set_entry` проверяет версию только при изменении существующего каталога.
+var versionedCmds = map[string]bool{
+	"set_entry":         true,
+	"rename_entry":      true,
+	"finalyze_entry":    true,
+	"unfinalyze_entry":  true,
+	"accept_suggestion": true,
+	"revert_entry":      true,
+}
+
+// Проверяет, безопасен ли повтор запроса `req`.
+func (c *Client) retryable(req *AudioDBRequest) bool {
+	if idempotentCmds[req.Cmd] {
+		return true
+	}
+	if !c.retryAll || !versionedCmds[req.Cmd] || req.Entry == nil || req.Entry.Version == 0 {
+		return false
+	}
+	return req.Cmd != "set_entry" || req.Entry.ID != 0
+}
+
+// ClientOption описывает опцию клиента.
+type ClientOption func(*Client)
+
+// WithRetries задает число повторов запроса при временной недоступности сервиса
+// и паузу перед первым повтором. Пауза удваивается перед каждым следующим повтором.
+// Повторяются только команды, не изменяющие данные (см. WithRetryAll()).
+func WithRetries(n int, backoff time.Duration) ClientOption {
+	return func(c *Client) {
+		c.retries = n
+		c.backoff = backoff
+	}
+}
+
+// WithRetryAll включает повтор команд, изменяющих данные, если команда проверяет версию
+// каталога (`set_entry` для существующего каталога, `rename_entry`, `finalyze_entry`,
+// `unfinalyze_entry`, `accept_suggestion`, `revert_entry`) и запрос содержит ненулевую версию.
+// Если ответ на предыдущую попытку был потерян, повтор завершается конфликтом версий.
+// Команды без проверки версии (`delete_entry`, `reject_suggestion`, `move_tree`, `reconcile`)
+// и запросы без версии не повторяются.
+func WithRetryAll() ClientOption {
+	return func(c *Client) {
+		c.retryAll = true
+	}
+}
+
+// NewClient создает клиента сервиса, работающего через транспорт `tr`.
+func NewClient(tr Transport, opts ...ClientOption) *Client {
+	c := &Client{tr: tr}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Do выполняет запрос и возвращает данные ответа сервиса.
+// При конфликте версий Entry наряду с ошибкой возвращается текущее состояние каталога,
+// при коллизиях путей перемещения дерева каталогов - план перемещения.
+// Запрос повторяется, если сервис временно недоступен (см. WithRetries()).
+// Если срок действия контекста истекает в паузе перед повтором, возвращается ошибка контекста.
+func (c *Client) Do(ctx context.Context, req *AudioDBRequest) (_ *AudioDBRequest, err error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	retries := c.retries
+	if !c.retryable(req) {
+		retries = 0
+	}
+	delay := c.backoff
+	for attempt := 0; ; attempt++ {
+		var resp *AudioDBRequest
+		resp, err = c.roundTrip(ctx, body)
+		if err == nil || attempt == retries || !transient(err) {
+			return resp, err
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+		delay *= 2
+	}
+}
+
+// Выполняет одну попытку запроса.
+func (c *Client) roundTrip(ctx context.Context, body []byte) (*AudioDBRequest, error) {
+	data, err := c.tr.RoundTrip(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	resp, err := ParseAnswer(data)
+	if err != nil {
+		// ответ на запрос, который не удалось разобрать, содержит только сведения об ошибке
+		var errResp ErrorResponse
+		if json.Unmarshal(data, &errResp) != nil || errResp.Error == "" {
+			return nil, errors.Wrap(err, "failed to parse answer")
+		}
+		return nil, &ResponseError{
+			Code:    errResp.Code,
+			Message: errResp.Error,
+			Context: errResp.Context}
+	}
+	return resp.AudioDBRequest, resp.Err()
+}
+
+// Проверяет, может ли повтор запроса завершиться успешно.
+func transient(err error) bool {
+	if errors.Is(err, ErrDBUnavailable) || errors.Is(err, ErrPublishFailed) {
+		return true
+	}
+	var temp interface{ Temporary() bool }
+	return errors.As(err, &temp) && temp.Temporary()
+}
+
+// Ping проверяет доступность сервиса.
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.Do(ctx, &AudioDBRequest{Cmd: "ping"})
+	return err
+}
+
+// GetEntry возвращает данные каталога с идентификатором или путем из `entry`.
+func (c *Client) GetEntry(ctx context.Context, entry *entity.AlbumEntry) (*AudioDBRequest, error) {
+	return c.Do(ctx, NewAudioDBRequest("get_entry", entry))
+}
+
+// SetEntry создает или обновляет каталог и связанные с ним данные из запроса `req`.
+func (c *Client) SetEntry(ctx context.Context, req *AudioDBRequest) (*AudioDBRequest, error) {
+	r := *req
+	r.Cmd = "set_entry"
+	return c.Do(ctx, &r)
+}
+
+// DeleteEntry удаляет каталог и связанные с ним данные.
+func (c *Client) DeleteEntry(ctx context.Context, entry *entity.AlbumEntry) error {
+	_, err := c.Do(ctx, NewAudioDBRequest("delete_entry", entry))
+	return err
+}
+
+// FinalyzeEntry переводит каталог в статус entity.StatusFinalyzed.
+func (c *Client) FinalyzeEntry(ctx context.Context, entry *entity.AlbumEntry) (*AudioDBRequest, error) {
+	return c.Do(ctx, NewAudioDBRequest("finalyze_entry", entry))
+}
+
+// UnfinalyzeEntry снимает с каталога статус entity.StatusFinalyzed.
+func (c *Client) UnfinalyzeEntry(
+	ctx context.Context, entry *entity.AlbumEntry) (*AudioDBRequest, error) {
+
+	return c.Do(ctx, NewAudioDBRequest("unfinalyze_entry", entry))
+}
+
+// RenameEntry изменяет путь каталога на `newPath`.
+func (c *Client) RenameEntry(
+	ctx context.Context, entry *entity.AlbumEntry, newPath string) (*AudioDBRequest, error) {
+
+	req := NewAudioDBRequest("rename_entry", entry)
+	req.NewPath = newPath
+	return c.Do(ctx, req)
+}
+
+// MoveTree переносит каталоги с префиксом пути `oldPrefix` под префикс `newPrefix`.
+// При `dryRun` возвращается только план перемещения.
+func (c *Client) MoveTree(
+	ctx context.Context, oldPrefix, newPrefix string, dryRun bool) (*AudioDBRequest, error) {
+
+	req := NewAudioDBRequest("move_tree", nil)
+	req.OldPrefix, req.NewPrefix, req.DryRun = oldPrefix, newPrefix, dryRun
+	return c.Do(ctx, req)
+}
+
+// ListEntries возвращает страницу каталогов, соответствующих фильтру,
+// и курсор следующей страницы (пустой для последней страницы).
+func (c *Client) ListEntries(
+	ctx context.Context, filter *entity.EntryFilter) (_ []*entity.AlbumEntry, next string, err error) {
+
+	req := NewAudioDBRequest("list_entries", nil)
+	req.Filter = filter
+	resp, err := c.Do(ctx, req)
+	if err != nil {
+		return nil, "", err
+	}
+	return resp.Entries, resp.NextCursor, nil
+}
+
+// SearchEntries выполняет полнотекстовый поиск по метаданным каталогов.
+func (c *Client) SearchEntries(
+	ctx context.Context, search *entity.EntrySearch) ([]*entity.SearchResult, error) {
+
+	req := NewAudioDBRequest("search_entries", nil)
+	req.Search = search
+	resp, err := c.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.SearchResults, nil
+}
+
+// AcceptSuggestion принимает предложение внешней БД `extDB` с идентификатором `extID`
+// в качестве метаданных каталога с политикой слияния `mergePolicy`.
+func (c *Client) AcceptSuggestion(ctx context.Context,
+	entry *entity.AlbumEntry, extDB, extID, mergePolicy string) (*AudioDBRequest, error) {
+
+	req := NewAudioDBRequest("accept_suggestion", entry)
+	req.ExtDB, req.ExtID, req.MergePolicy = extDB, extID, mergePolicy
+	return c.Do(ctx, req)
+}
+
+// RejectSuggestion отклоняет предложение внешней БД `extDB` с идентификатором `extID`.
+func (c *Client) RejectSuggestion(
+	ctx context.Context, entry *entity.AlbumEntry, extDB, extID string) error {
+
+	req := NewAudioDBRequest("reject_suggestion", entry)
+	req.ExtDB, req.ExtID = extDB, extID
+	_, err := c.Do(ctx, req)
+	return err
+}
+
+// GetEntryHistory возвращает ревизии каталога.
+func (c *Client) GetEntryHistory(
+	ctx context.Context, entry *entity.AlbumEntry) ([]*entity.EntryRevision, error) {
+
+	resp, err := c.Do(ctx, NewAudioDBRequest("get_entry_history", entry))
+	if err != nil {
+		return nil, err
+	}
+	return resp.Revisions, nil
+}
+
+// RevertEntry восстанавливает состояние каталога из ревизии `revisionID`.
+func (c *Client) RevertEntry(
+	ctx context.Context, entry *entity.AlbumEntry, revisionID int) (*AudioDBRequest, error) {
+
+	req := NewAudioDBRequest("revert_entry", entry)
+	req.RevisionID = revisionID
+	return c.Do(ctx, req)
+}
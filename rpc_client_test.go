@@ -0,0 +1,167 @@
+package dbm
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+)
+
+// Транспорт, возвращающий подготовленные ответы.
+type stubTransport struct {
+	answers [][]byte
+	errs    []error
+	calls   int
+}
+
+func (t *stubTransport) RoundTrip(ctx context.Context, body []byte) ([]byte, error) {
+	i := t.calls
+	t.calls++
+	if i < len(t.errs) && t.errs[i] != nil {
+		return nil, t.errs[i]
+	}
+	if i < len(t.answers) {
+		return t.answers[i], nil
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestClient(t *testing.T) {
+	m := New("", WithStore(entity.NewMemStore()))
+	defer m.Close()
+	tr := NewInProcTransport(m)
+	defer tr.Close()
+	c := NewClient(tr)
+	ctx := context.Background()
+
+	require.NoError(t, c.Ping(ctx))
+
+	req := NewAudioDBRequest("", &entity.AlbumEntry{
+		Path: "a", Json: []byte(`{}`), Status: entity.StatusWithoutMandatoryTags})
+	resp, err := c.SetEntry(ctx, req)
+	require.NoError(t, err)
+	assert.Empty(t, req.Cmd)
+	entry := &entity.AlbumEntry{ID: resp.Entry.ID}
+
+	resp, err = c.GetEntry(ctx, entry)
+	require.NoError(t, err)
+	assert.Equal(t, "a", resp.Entry.Path)
+	version := resp.Entry.Version
+
+	req = NewAudioDBRequest("", &entity.AlbumEntry{
+		ID: entry.ID, Path: "a", Status: entity.StatusWithoutMandatoryTags, Version: version + 1})
+	resp, err = c.SetEntry(ctx, req)
+	assert.ErrorIs(t, err, ErrConflict)
+	require.NotNil(t, resp)
+	assert.Equal(t, version, resp.Entry.Version)
+
+	resp, err = c.RenameEntry(ctx, entry, "b")
+	require.NoError(t, err)
+	assert.Equal(t, "b", resp.NewPath)
+
+	resp, err = c.FinalyzeEntry(ctx, entry)
+	require.NoError(t, err)
+	assert.Equal(t, entity.StatusFinalyzed, resp.Entry.Status)
+	_, err = c.FinalyzeEntry(ctx, entry)
+	assert.ErrorIs(t, err, ErrAlreadyFinalyzed)
+
+	entries, next, err := c.ListEntries(ctx, &entity.EntryFilter{})
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Empty(t, next)
+
+	require.NoError(t, c.DeleteEntry(ctx, entry))
+	_, err = c.GetEntry(ctx, entry)
+	assert.ErrorIs(t, err, ErrNotFound)
+	var respErr *ResponseError
+	require.ErrorAs(t, err, &respErr)
+	assert.Equal(t, CodeNotFound, respErr.Code)
+
+	_, err = c.Do(ctx, NewAudioDBRequest("x", entry))
+	assert.ErrorIs(t, err, ErrBadRequest)
+}
+
+func TestClientRetries(t *testing.T) {
+	unavailable := []byte(`{"error":{"error":"db is down","context":"get_entry","code":"db_unavailable"}}`)
+	ok := []byte(`{"entry":{"id":1,"path":"a"}}`)
+	ctx := context.Background()
+
+	tr := &stubTransport{answers: [][]byte{unavailable, nil, ok},
+		errs: []error{nil, &net.DNSError{IsTemporary: true}}}
+	c := NewClient(tr, WithRetries(2, time.Millisecond))
+	resp, err := c.GetEntry(ctx, &entity.AlbumEntry{ID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "a", resp.Entry.Path)
+	assert.Equal(t, 3, tr.calls)
+
+	// число повторов ограничено
+	tr = &stubTransport{answers: [][]byte{unavailable, unavailable}}
+	_, err = NewClient(tr, WithRetries(1, time.Millisecond)).GetEntry(ctx, &entity.AlbumEntry{ID: 1})
+	assert.ErrorIs(t, err, ErrDBUnavailable)
+	assert.Equal(t, 2, tr.calls)
+
+	// ошибки, не связанные с доступностью сервиса, не повторяются
+	tr = &stubTransport{answers: [][]byte{
+		[]byte(`{"error":{"error":"no rows","context":"get_entry","code":"not_found"}}`)}}
+	_, err = NewClient(tr, WithRetries(3, time.Millisecond)).GetEntry(ctx, &entity.AlbumEntry{ID: 1})
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, 1, tr.calls)
+
+	// ошибка публикации запроса считается временной, закрытый транспорт - нет
+	tr = &stubTransport{answers: [][]byte{nil, ok}, errs: []error{ErrPublishFailed}}
+	_, err = NewClient(tr, WithRetries(1, time.Millisecond)).GetEntry(ctx, &entity.AlbumEntry{ID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, tr.calls)
+	tr = &stubTransport{answers: [][]byte{nil, ok}, errs: []error{ErrTransportClosed}}
+	_, err = NewClient(tr, WithRetries(3, time.Millisecond)).GetEntry(ctx, &entity.AlbumEntry{ID: 1})
+	assert.ErrorIs(t, err, ErrTransportClosed)
+	assert.Equal(t, 1, tr.calls)
+
+	// команды, изменяющие данные, повторяются только с опцией WithRetryAll()
+	// и только при проверке версии каталога
+	rename := NewAudioDBRequest("rename_entry", &entity.AlbumEntry{ID: 1, Version: 2})
+	rename.NewPath = "b"
+	tr = &stubTransport{answers: [][]byte{nil, ok}, errs: []error{ErrPublishFailed}}
+	_, err = NewClient(tr, WithRetries(1, time.Millisecond)).Do(ctx, rename)
+	assert.ErrorIs(t, err, ErrPublishFailed)
+	assert.Equal(t, 1, tr.calls)
+	tr = &stubTransport{answers: [][]byte{nil, ok}, errs: []error{ErrPublishFailed}}
+	_, err = NewClient(tr, WithRetries(1, time.Millisecond), WithRetryAll()).Do(ctx, rename)
+	require.NoError(t, err)
+	assert.Equal(t, 2, tr.calls)
+	for _, req := range []*AudioDBRequest{
+		NewAudioDBRequest("rename_entry", &entity.AlbumEntry{ID: 1}),
+		NewAudioDBRequest("set_entry", &entity.AlbumEntry{Path: "a", Version: 2}),
+		NewAudioDBRequest("delete_entry", &entity.AlbumEntry{ID: 1, Version: 2}),
+	} {
+		tr = &stubTransport{answers: [][]byte{nil, ok}, errs: []error{ErrPublishFailed}}
+		_, err = NewClient(tr, WithRetries(1, time.Millisecond), WithRetryAll()).Do(ctx, req)
+		assert.ErrorIs(t, err, ErrPublishFailed, req.Cmd)
+		assert.Equal(t, 1, tr.calls, req.Cmd)
+	}
+}
+
+func TestClientDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// ожидание ответа ограничено контекстом
+	tr := &stubTransport{}
+	_, err := NewClient(tr).GetEntry(ctx, &entity.AlbumEntry{ID: 1})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// пауза перед повтором не превышает срок действия контекста
+	unavailable := []byte(`{"error":{"error":"db is down","code":"db_unavailable"}}`)
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	tr = &stubTransport{answers: [][]byte{unavailable, unavailable}}
+	_, err = NewClient(tr, WithRetries(1, time.Hour)).GetEntry(ctx, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, tr.calls)
+}
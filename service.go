@@ -6,100 +6,315 @@ package dbm
 import (
 	"context"
 	"encoding/json"
+	"io"
+	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"sync"
 	"syscall"
+	"time"
 
-	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/pkg/errors"
 	"github.com/streadway/amqp"
+	"google.golang.org/grpc"
 
 	"github.com/ytsiuryn/ds-audiodbm/entity"
+	"github.com/ytsiuryn/ds-audiodbm/migrations"
 	srv "github.com/ytsiuryn/ds-microservice"
 	"github.com/ytsiuryn/go-collection"
 )
 
 // ConnType описывает перечисление типов соединений.
-type ConnType string
-
-func (ct ConnType) String() string {
-	return string(ct)
-}
+type ConnType = entity.ConnType
 
 // Допустимые типы соединений
 const (
-	NormalConnType      = ConnType("db")
-	TransactionConnType = ConnType("tx")
+	NormalConnType      = entity.NormalConnType
+	TransactionConnType = entity.TransactionConnType
 )
 
 // Константы сервиса
 const (
-	ServiceName = "dbmaudio"
+	ServiceName    = "dbmaudio"
+	DefaultWorkers = 1
 )
 
 // Dbm описывает внутреннее состояние клиента Discogs.
 type Dbm struct {
 	*srv.Service
-	ctx  context.Context
-	conn *pgx.Conn
+	ctx         context.Context
+	pool        *pgxpool.Pool
+	storeCloser io.Closer // хранилище, открытое по строке подключения
+	store       entity.Store
+	amqpConn    *amqp.Connection
+	amqpCh      *amqp.Channel
+	disp        *dispatcher
+	workers     int
+	poolSize    int32
+	healthCheck time.Duration
+	mergePolicy string
+	migrate     bool
+
+	mandatoryTags []string
+
+	libraryRoot     string
+	reconcilePeriod time.Duration
+	orphanAction    string
+	maxOrphanRatio  float64
+	reconcileMu     sync.Mutex
+	stopReconcile   context.CancelFunc
+	reconcileDone   chan struct{}
+
+	httpAddr   string
+	httpServer *http.Server
+	httpDone   chan struct{}
+
+	grpcAddr   string
+	grpcServer *grpc.Server
+	grpcDone   chan struct{}
+
+	watchDebounce time.Duration
+	stopWatcher   context.CancelFunc
+	watcherDone   chan struct{}
+
+	eventExchange  string
+	eventNotify    chan struct{}
+	stopEventRelay context.CancelFunc
+	eventRelayDone chan struct{}
+}
+
+// Option описывает функцию настройки менеджера БД.
+type Option func(*Dbm)
+
+// WithWorkers задает число одновременно обрабатываемых запросов.
+// Это же значение используется как prefetch count для брокера сообщений.
+func WithWorkers(n int) Option {
+	return func(m *Dbm) {
+		if n > 0 {
+			m.workers = n
+		}
+	}
+}
+
+// WithPoolSize задает максимальное число соединений в пуле соединений с БД.
+// По умолчанию используется значение параметра `pool_max_conns` строки подключения
+// или значение по умолчанию пакета pgxpool.
+func WithPoolSize(n int) Option {
+	return func(m *Dbm) {
+		if n > 0 {
+			m.poolSize = int32(n)
+		}
+	}
+}
+
+// WithHealthCheckPeriod задает период проверки работоспособности свободных соединений пула.
+// Разорванные соединения (например, после перезапуска PostgreSQL) закрываются пулом
+// и заменяются новыми при очередном запросе.
+func WithHealthCheckPeriod(d time.Duration) Option {
+	return func(m *Dbm) {
+		if d > 0 {
+			m.healthCheck = d
+		}
+	}
+}
+
+// WithMergePolicy задает политику слияния метаданных по умолчанию для команды
+// accept_suggestion.
+func WithMergePolicy(policy string) Option {
+	return func(m *Dbm) {
+		m.mergePolicy = policy
+	}
+}
+
+// WithMigrate включает создание схемы БД и применение новых миграций при создании сервиса.
+func WithMigrate() Option {
+	return func(m *Dbm) {
+		m.migrate = true
+	}
+}
+
+// WithStore задает хранилище данных сервиса вместо хранилища PostgreSQL.
+// Строка подключения к БД в этом случае не используется, миграции схемы БД не применяются.
+func WithStore(store entity.Store) Option {
+	return func(m *Dbm) {
+		m.store = store
+	}
 }
 
 // New создает объект менеджера БД для аудио.
-func New(dbURL string) *Dbm {
-	dbm := &Dbm{Service: srv.NewService(ServiceName)}
+// Строка подключения со схемой, для которой зарегистрировано хранилище (entity.RegisterStore()),
+// например "sqlite://<путь к файлу>" при импорте пакета entity/sqlitestore, выбирает это
+// хранилище; любая другая строка подключения используется для PostgreSQL.
+// Работа сервиса прекращается, если версия схемы БД не совпадает с версией последней
+// встроенной миграции.
+func New(dbURL string, opts ...Option) *Dbm {
+	dbm := &Dbm{
+		Service:        srv.NewService(ServiceName),
+		workers:        DefaultWorkers,
+		mergePolicy:    MergeOverwrite,
+		mandatoryTags:  DefaultMandatoryTags,
+		orphanAction:   OrphanReport,
+		maxOrphanRatio: DefaultMaxOrphanRatio,
+		eventNotify:    make(chan struct{}, 1)}
+	for _, opt := range opts {
+		opt(dbm)
+	}
+	dbm.disp = newDispatcher(dbm.workers)
+	if err := checkMandatoryTags(dbm.mandatoryTags); err != nil {
+		dbm.Log.Fatalln(err)
+	}
+	dbm.ctx = context.Background()
+	if dbm.store == nil {
+		dbm.store = dbm.openStore(dbURL)
+	}
+
+	return dbm
+}
 
-	conn, err := pgx.Connect(context.Background(), dbURL)
+// Подключается к PostgreSQL, при необходимости применяет миграции и проверяет версию
+// схемы БД.
+func (m *Dbm) connectToDB(dbURL string) entity.Store {
+	cfg, err := pgxpool.ParseConfig(dbURL)
 	if err != nil {
-		dbm.Log.Fatalln(err)
+		m.Log.Fatalln(err)
+	}
+	if m.poolSize > 0 {
+		cfg.MaxConns = m.poolSize
+	}
+	if m.healthCheck > 0 {
+		cfg.HealthCheckPeriod = m.healthCheck
 	}
 
-	dbm.ctx = context.WithValue(context.Background(), NormalConnType, conn)
+	m.pool, err = pgxpool.ConnectConfig(context.Background(), cfg)
+	if err != nil {
+		m.Log.Fatalln(err)
+	}
 
-	return dbm
+	if m.migrate {
+		applied, err := migrations.Up(context.Background(), m.pool)
+		if err != nil {
+			m.Log.Fatalln(err)
+		}
+		for _, version := range applied {
+			m.Log.Infof("migration %d applied", version)
+		}
+	}
+	if err = migrations.Check(context.Background(), m.pool); err != nil {
+		m.Log.Fatalln(err)
+	}
+
+	return entity.NewPgStore(m.pool)
+}
+
+// Открывает хранилище, зарегистрированное для схемы строки подключения, или подключается
+// к PostgreSQL.
+func (m *Dbm) openStore(dbURL string) entity.Store {
+	store, err := entity.OpenStore(context.Background(), dbURL, m.migrate)
+	if errors.Is(err, entity.ErrUnknownStore) {
+		return m.connectToDB(dbURL)
+	}
+	if err != nil {
+		m.Log.Fatalln(err)
+	}
+	m.storeCloser, _ = store.(io.Closer)
+	return store
 }
 
 // AnswerWithError заполняет структуру ответа информацией об ошибке.
 func (m *Dbm) AnswerWithError(delivery *amqp.Delivery, err error, context string) {
-	m.LogOnErrorWithContext(err, context)
-	req := &AudioDBResponse{
-		Error: &srv.ErrorResponse{
-			Error:   err.Error(),
-			Context: context,
-		},
-	}
-	data, err := json.Marshal(req)
-	srv.FailOnError(err, "Answer marshalling")
-	m.Answer(delivery, data)
+	m.Answer(delivery, m.errorAnswer(nil, err, context))
 }
 
 // StartWithConnection запускает осноной цикл обработки команд запроса.
+// Запросы обрабатываются параллельно, но не более `workers` одновременно.
+// Команды, относящиеся к одному и тому же Entry, выполняются в порядке поступления.
+// Ограничение и порядок выполнения общие для всех транспортов сервиса (брокер сообщений,
+// HTTP, gRPC, InProcTransport).
 func (m *Dbm) StartWithConnection(connstr string) {
-	msgs := m.Service.ConnectToMessageBroker(connstr)
+	msgs := m.connectToMessageBroker(connstr, m.workers)
 
-	c := make(chan os.Signal)
+	if m.eventExchange != "" {
+		m.startEventRelay()
+	}
+	if m.libraryRoot != "" && m.reconcilePeriod > 0 {
+		m.startReconcileJob()
+	}
+	if m.libraryRoot != "" && m.watchDebounce > 0 {
+		m.startWatcher()
+	}
+	if m.httpAddr != "" {
+		m.startHTTP()
+	}
+	if m.grpcAddr != "" {
+		m.startGRPC()
+	}
+
+	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 
+	done := make(chan struct{})
 	go func() {
+		defer close(done)
 		for delivery := range msgs {
+			delivery := delivery
 			var req AudioDBRequest
 			if err := json.Unmarshal(delivery.Body, &req); err != nil {
 				m.AnswerWithError(&delivery, err, "Message dispatcher")
 				continue
 			}
-			m.logRequest(&req)
-			m.RunCmd(&req, &delivery)
+			m.disp.Dispatch(m.entryKey(&req), func() {
+				m.logRequest(&req)
+				m.RunCmd(&req, &delivery)
+			})
 		}
 	}()
 
 	m.Log.Info("Awaiting RPC requests")
 	<-c
 
+	m.stopConsuming()
+	<-done
+	m.disp.Wait()
+
+	m.cleanup()
+}
+
+// Close останавливает фоновые задачи сервиса и освобождает подключения.
+// Используется при работе сервиса без брокера сообщений (см. InProcTransport);
+// StartWithConnection освобождает ресурсы самостоятельно.
+func (m *Dbm) Close() {
 	m.cleanup()
 }
 
 func (m *Dbm) cleanup() {
-	m.conn.Close(m.ctx)
-	m.Service.Cleanup()
+	if m.httpServer != nil {
+		m.stopHTTP()
+	}
+	if m.grpcServer != nil {
+		m.stopGRPC()
+	}
+	if m.stopWatcher != nil {
+		m.stopWatcher()
+		<-m.watcherDone
+	}
+	if m.stopReconcile != nil {
+		m.stopReconcile()
+		<-m.reconcileDone
+	}
+	if m.stopEventRelay != nil {
+		m.stopEventRelay()
+		<-m.eventRelayDone
+	}
+	if m.pool != nil {
+		m.pool.Close()
+	}
+	if m.storeCloser != nil {
+		m.storeCloser.Close()
+	}
+	m.disconnectFromMessageBroker()
+	m.Log.Infoln("stopped")
 }
 
 // Отображение сведений о выполняемом запросе.
@@ -111,10 +326,55 @@ func (m *Dbm) logRequest(req *AudioDBRequest) {
 	}
 }
 
+// Ключ упорядочивания команд: ID записи, а для новых записей - путь к каталогу.
+func (req *AudioDBRequest) entryKey() string {
+	switch {
+	case req.Entry == nil:
+		return ""
+	case req.Entry.ID != 0:
+		return "id:" + strconv.Itoa(req.Entry.ID)
+	case req.Entry.Path != "":
+		return "path:" + req.Entry.Path
+	}
+	return ""
+}
+
+// Возвращает ключ упорядочивания команды запроса.
+// Команды, затрагивающие множество записей (move_tree, reconcile), выполняются
+// не одновременно с другими командами. Путь к каталогу существующей записи заменяется
+// ее ID, поэтому команды, адресующие запись по пути и по ID, выполняются в порядке
+// поступления. Путь разрешается в момент постановки команды в очередь: команда для пути,
+// который еще не занят (например, создание записи), упорядочивается только с командами
+// для того же пути.
+func (m *Dbm) entryKey(req *AudioDBRequest) string {
+	switch req.Cmd {
+	case "move_tree", "reconcile":
+		return exclusiveKey
+	}
+	if req.Entry != nil && req.Entry.ID == 0 && req.Entry.Path != "" {
+		entry := &entity.AlbumEntry{Path: req.Entry.Path}
+		if err := m.store.GetEntry(m.ctx, entry); err == nil {
+			return "id:" + strconv.Itoa(entry.ID)
+		}
+	}
+	return req.entryKey()
+}
+
 // RunCmd выполняет команды и возвращает результат клиенту в виде JSON-сообщения.
 func (m *Dbm) RunCmd(req *AudioDBRequest, delivery *amqp.Delivery) {
-	var data []byte
-	var err error
+	m.Answer(delivery, m.handle(req))
+}
+
+// Execute выполняет команду `req.Cmd` без участия брокера сообщений и возвращает
+// результат в виде JSON-сообщения. Запрос `req` заполняется данными результата.
+func (m *Dbm) Execute(req *AudioDBRequest) (data []byte, err error) {
+	switch req.Cmd {
+	case "list_entries", "search_entries", "move_tree", "reconcile":
+	default:
+		if req.Entry == nil {
+			return nil, ErrEntryRequired
+		}
+	}
 
 	switch req.Cmd {
 	case "get_entry":
@@ -125,75 +385,140 @@ func (m *Dbm) RunCmd(req *AudioDBRequest, delivery *amqp.Delivery) {
 		data, err = m.deleteEntry(req)
 	case "finalyze_entry":
 		data, err = m.finalyzeEntry(req)
+	case "unfinalyze_entry":
+		data, err = m.unfinalyzeEntry(req)
 	case "rename_entry":
 		data, err = m.renameEntry(req)
+	case "move_tree":
+		data, err = m.moveTree(req)
+	case "reconcile":
+		data, err = m.reconcile(req)
+	case "list_entries":
+		data, err = m.listEntries(req)
+	case "search_entries":
+		data, err = m.searchEntries(req)
+	case "accept_suggestion":
+		data, err = m.acceptSuggestion(req)
+	case "reject_suggestion":
+		data, err = m.rejectSuggestion(req)
+	case "get_entry_history":
+		data, err = m.getEntryHistory(req)
+	case "diff_entry_revisions":
+		data, err = m.diffEntryRevisions(req)
+	case "revert_entry":
+		data, err = m.revertEntry(req)
 	default:
-		m.Service.RunCmd(req.Cmd, delivery)
-		return
-	}
-
-	if err != nil {
-		m.AnswerWithError(delivery, err, req.Cmd)
-	} else {
-		m.Answer(delivery, data)
+		return nil, errors.Wrap(ErrUnknownCommand, req.Cmd)
 	}
+	return
 }
 
 // Чтение информации по Entry ID или его пути.
 // Заполняются таблицы запроса `AudioDBRequest` и для него формируется JSON.
 func (m *Dbm) getEntry(req *AudioDBRequest) (_ []byte, err error) {
-	if err = req.Entry.Get(m.ctx); err != nil {
+	if err = m.loadEntry(req); err != nil {
 		return
 	}
-	req.Actors, err = entity.EntryActors(m.ctx, req.Entry.ID)
+	return json.Marshal(req)
+}
+
+// Заполняет запрос текущими данными Entry и связанных с ним объектов.
+func (m *Dbm) loadEntry(req *AudioDBRequest) (err error) {
+	if err = m.store.GetEntry(m.ctx, req.Entry); err != nil {
+		return
+	}
+	req.Transitions = entity.StatusTransitions(req.Entry.Status)
+	if req.MissingTags, err = missingTags(req.Entry.Json, m.mandatoryTags); err != nil {
+		return
+	}
+	req.Actors, err = m.store.EntryActors(m.ctx, req.Entry.ID)
 	if err != nil {
 		return
 	}
-	req.Pictures, err = entity.EntryPictures(m.ctx, req.Entry.ID)
+	req.Pictures, err = m.store.EntryPictures(m.ctx, req.Entry.ID)
 	if err != nil {
 		return
 	}
-	req.Suggestions, err = entity.EntrySuggestions(m.ctx, req.Entry.ID)
+	req.Suggestions, err = m.store.EntrySuggestions(m.ctx, req.Entry.ID)
 	if err != nil {
 		return
 	}
-	req.BadSuggestions, err = entity.EntryBadSuggestions(m.ctx, req.Entry.ID)
+	req.BadSuggestions, err = m.store.EntryBadSuggestions(m.ctx, req.Entry.ID)
 	if err != nil {
 		return
 	}
-	return json.Marshal(req)
+	accepted := &entity.AcceptedSuggestion{EntryID: req.Entry.ID}
+	if err = m.store.GetAcceptedSuggestion(m.ctx, accepted); err == nil {
+		req.Accepted = accepted
+	} else if !errors.Is(err, entity.ErrNotFound) {
+		return
+	}
+	return nil
 }
 
 // Создание записи или изменение существующих данных по каталогу.
 // В случае успеха возвращает ID записи Entry.
 func (m *Dbm) setEntry(req *AudioDBRequest) (_ []byte, err error) {
-	var tx pgx.Tx
-	tx, err = m.conn.Begin(m.ctx)
+	txctx, tx, err := m.begin(m.ctx)
 	if err != nil {
 		return
 	}
-	defer m.completeTx(tx, err)
-	txctx := context.WithValue(m.ctx, TransactionConnType, tx)
+	defer m.completeTx(tx, &err)
 	req.Entry.LastModified = req.Entry.LastModified.UTC()
+	ev := &entity.EntryEvent{Cmd: req.Cmd}
 	if req.Entry.ID == 0 {
-		err = req.Entry.Create(txctx)
+		ev.Type = entity.EntryCreated
+		if err = m.evalEntryStatus(req, ""); err != nil {
+			return
+		}
+		err = m.store.CreateEntry(txctx, req.Entry)
 	} else {
-		err = req.Entry.Update(txctx)
+		ev.Type = entity.EntryUpdated
+		old := &entity.AlbumEntry{ID: req.Entry.ID}
+		if err = m.store.GetEntry(txctx, old); err != nil {
+			return
+		}
+		if err = checkNotFinalyzed(old); err != nil {
+			return
+		}
+		if err = m.evalEntryStatus(req, old.Status); err != nil {
+			return
+		}
+		ev.OldPath, ev.OldStatus = old.Path, old.Status
+		ev.Changed = entryChanges(old, req.Entry)
+		if err = m.store.SaveEntryRevision(txctx, req.Entry.ID, req.Cmd); err != nil {
+			return
+		}
+		err = m.store.UpdateEntry(txctx, req.Entry)
 	}
 	if err != nil {
 		return
 	}
-	if err = syncEntryPictures(txctx, req); err != nil {
-		return
-	}
-	if err = syncEntryActors(txctx, req); err != nil {
-		return
+	for _, sync := range []struct {
+		name string
+		fn   func(context.Context, *AudioDBRequest) (bool, error)
+	}{
+		{"pictures", m.syncEntryPictures},
+		{"actors", m.syncEntryActors},
+		{"suggestions", m.syncSuggestions},
+		{"bad_suggestions", m.syncBadSuggestions},
+	} {
+		var changed bool
+		if changed, err = sync.fn(txctx, req); err != nil {
+			return
+		}
+		if changed {
+			ev.Changed = append(ev.Changed, sync.name)
+		}
 	}
-	if err = syncSuggestions(txctx, req); err != nil {
-		return
+	ev.EntryID, ev.Path, ev.NewStatus = req.Entry.ID, req.Entry.Path, req.Entry.Status
+	if ev.OldPath == ev.Path {
+		ev.OldPath = ""
 	}
-	if err = syncBadSuggestions(txctx, req); err != nil {
-		return
+	if ev.Type == entity.EntryCreated || len(ev.Changed) > 0 {
+		if err = m.recordEvent(txctx, ev); err != nil {
+			return
+		}
 	}
 	return json.Marshal(req)
 }
@@ -202,37 +527,60 @@ func (m *Dbm) setEntry(req *AudioDBRequest) (_ []byte, err error) {
 // В случае успеха возвращает пустую байтовую последовательность.
 func (m *Dbm) deleteEntry(req *AudioDBRequest) (_ []byte, err error) {
 
-	var tx pgx.Tx
-	tx, err = m.conn.Begin(m.ctx)
+	txctx, tx, err := m.begin(m.ctx)
 	if err != nil {
 		return
 	}
-	defer m.completeTx(tx, err)
+	defer m.completeTx(tx, &err)
 	if req.Entry.ID == 0 {
-		err = req.Entry.Get(m.ctx)
-		if err != nil && errors.Cause(err) != pgx.ErrNoRows {
+		err = m.store.GetEntry(txctx, req.Entry)
+		if err != nil && !errors.Is(err, entity.ErrNotFound) {
 			return
 		}
 	}
-	txctx := context.WithValue(m.ctx, TransactionConnType, tx)
-	if err = entity.DeleteEntryPictures(txctx, req.Entry.ID); err != nil {
-		return
-	}
-	if err = entity.DeleteEntryActors(txctx, req.Entry.ID); err != nil {
-		return
-	}
-	if err = entity.DeleteEntryBadSuggestions(txctx, req.Entry.ID); err != nil {
-		return
+	old := &entity.AlbumEntry{ID: req.Entry.ID}
+	if old.ID != 0 {
+		if err = m.store.GetEntry(txctx, old); err != nil {
+			if !errors.Is(err, entity.ErrNotFound) {
+				return
+			}
+			old.ID = 0
+		}
 	}
-	if err = entity.DeleteEntrySuggestions(txctx, req.Entry.ID); err != nil {
+	if err = m.deleteEntryData(txctx, req.Entry); err != nil {
 		return
 	}
-	if err = req.Entry.Delete(txctx); err != nil {
-		return
+	if old.ID != 0 {
+		err = m.recordEvent(txctx, &entity.EntryEvent{
+			Type:      entity.EntryDeleted,
+			Cmd:       req.Cmd,
+			EntryID:   old.ID,
+			Path:      old.Path,
+			OldStatus: old.Status})
+		if err != nil {
+			return
+		}
 	}
 	return json.Marshal(req)
 }
 
+// Удаляет запись Entry и все связанные с ней данные.
+func (m *Dbm) deleteEntryData(ctx context.Context, entry *entity.AlbumEntry) (err error) {
+	for _, del := range []func(context.Context, int) error{
+		m.store.DeleteEntryPictures,
+		m.store.DeleteEntryActors,
+		m.store.DeleteEntryBadSuggestions,
+		m.store.DeleteEntrySuggestions,
+		m.store.DeleteEntryAcceptedSuggestion,
+		m.store.DeleteEntryRevisions,
+	} {
+		if err = del(ctx, entry.ID); err != nil {
+			return
+		}
+	}
+	return m.store.DeleteEntry(ctx, entry)
+}
+
 // finalyze закрывает Entry для дальнейшего редактирования.
 // При этом удаляются данные по Entry из таблиц:
 //
@@ -245,27 +593,49 @@ func (m *Dbm) deleteEntry(req *AudioDBRequest) (_ []byte, err error) {
 // В таблице audio.album_entry устанавливается статус "finalyzed".
 // В случае успеха возвращает пустую байтовую последовательность.
 func (m *Dbm) finalyzeEntry(req *AudioDBRequest) (_ []byte, err error) {
-	var tx pgx.Tx
-	tx, err = m.conn.Begin(m.ctx)
+	txctx, tx, err := m.begin(m.ctx)
 	if err != nil {
 		return
 	}
-	defer m.completeTx(tx, err)
-	txctx := context.WithValue(m.ctx, TransactionConnType, tx)
+	defer m.completeTx(tx, &err)
 
-	if err = entity.DeleteEntrySuggestions(txctx, req.Entry.ID); err != nil {
+	expectedVersion := req.Entry.Version
+	if err = m.store.GetEntry(txctx, req.Entry); err != nil {
+		return
+	}
+	if err = checkNotFinalyzed(req.Entry); err != nil {
+		return
+	}
+	if err = entity.CheckStatusTransition(req.Entry.Status, entity.StatusFinalyzed); err != nil {
 		return
 	}
+	expectVersion(req.Entry, expectedVersion)
 
-	if err = entity.DeleteEntryBadSuggestions(txctx, req.Entry.ID); err != nil {
+	if err = m.store.DeleteEntrySuggestions(txctx, req.Entry.ID); err != nil {
 		return
 	}
 
-	if err = req.Entry.Get(m.ctx); err != nil {
+	if err = m.store.DeleteEntryBadSuggestions(txctx, req.Entry.ID); err != nil {
+		return
+	}
+	if err = m.store.SaveEntryRevision(txctx, req.Entry.ID, req.Cmd); err != nil {
 		return
 	}
-	req.Entry.Status = "finalyzed"
-	if err = req.Entry.Update(txctx); err != nil {
+	oldStatus := req.Entry.Status
+	req.Entry.Status = entity.StatusFinalyzed
+	if err = m.store.UpdateEntry(txctx, req.Entry); err != nil {
+		return
+	}
+
+	err = m.recordEvent(txctx, &entity.EntryEvent{
+		Type:      entity.EntryFinalyzed,
+		Cmd:       req.Cmd,
+		EntryID:   req.Entry.ID,
+		Path:      req.Entry.Path,
+		OldStatus: oldStatus,
+		NewStatus: req.Entry.Status,
+		Changed:   []string{"status", "suggestions", "bad_suggestions"}})
+	if err != nil {
 		return
 	}
 
@@ -273,21 +643,44 @@ func (m *Dbm) finalyzeEntry(req *AudioDBRequest) (_ []byte, err error) {
 }
 
 // renameEntry переименовывает наименование каталога альбома.
+// Финализированный каталог не переименовывается.
 // Возвращает эхо-ответ в случае успеха.
 func (m *Dbm) renameEntry(req *AudioDBRequest) (_ []byte, err error) {
-	tx, err := m.conn.Begin(m.ctx)
+	txctx, tx, err := m.begin(m.ctx)
 	if err != nil {
 		return
 	}
-	defer m.completeTx(tx, err)
+	defer m.completeTx(tx, &err)
 
 	entry := *req.Entry
-	if err = entry.Get(m.ctx); err != nil {
+	if err = m.store.GetEntry(txctx, &entry); err != nil {
+		return
+	}
+	if err = checkNotFinalyzed(&entry); err != nil {
 		return
 	}
+	expectVersion(&entry, req.Entry.Version)
 
+	oldPath := entry.Path
 	entry.Path = req.NewPath
-	err = entry.Update(context.WithValue(m.ctx, TransactionConnType, tx))
+	if err = m.store.SaveEntryRevision(txctx, entry.ID, req.Cmd); err != nil {
+		return
+	}
+	err = m.store.UpdateEntry(txctx, &entry)
+	if err != nil {
+		return
+	}
+	req.Entry.Version = entry.Version
+
+	err = m.recordEvent(txctx, &entity.EntryEvent{
+		Type:      entity.EntryRenamed,
+		Cmd:       req.Cmd,
+		EntryID:   entry.ID,
+		Path:      entry.Path,
+		OldPath:   oldPath,
+		OldStatus: entry.Status,
+		NewStatus: entry.Status,
+		Changed:   []string{"path"}})
 	if err != nil {
 		return
 	}
@@ -295,114 +688,353 @@ func (m *Dbm) renameEntry(req *AudioDBRequest) (_ []byte, err error) {
 	return json.Marshal(req)
 }
 
-func (m *Dbm) completeTx(tx pgx.Tx, err error) {
+// acceptSuggestion принимает предложение `req.ExtDB`/`req.ExtID` в качестве релиза каталога.
+// Метаданные релиза предложения объединяются с метаданными каталога по политике
+// `req.MergePolicy` (или политике сервиса по умолчанию), акторы предложения становятся
+// акторами каталога, а сведения о принятом предложении сохраняются в audio.accepted_suggestion.
+// Возвращает обновленные Entry, акторов и сведения о принятом предложении.
+func (m *Dbm) acceptSuggestion(req *AudioDBRequest) (_ []byte, err error) {
+	if req.MergePolicy == "" {
+		req.MergePolicy = m.mergePolicy
+	}
+
+	txctx, tx, err := m.begin(m.ctx)
 	if err != nil {
-		tx.Rollback(m.ctx)
-	} else {
-		tx.Commit(m.ctx)
+		return
+	}
+	defer m.completeTx(tx, &err)
+
+	expectedVersion := req.Entry.Version
+	if err = m.store.GetEntry(txctx, req.Entry); err != nil {
+		return
+	}
+	if err = checkNotFinalyzed(req.Entry); err != nil {
+		return
+	}
+	expectVersion(req.Entry, expectedVersion)
+	suggestion := &entity.Suggestion{EntryID: req.Entry.ID, ExtDB: req.ExtDB, ExtID: req.ExtID}
+	if err = m.store.GetSuggestion(txctx, suggestion); err != nil {
+		return
+	}
+
+	if err = m.store.SaveEntryRevision(txctx, req.Entry.ID, req.Cmd); err != nil {
+		return
+	}
+	oldJson, oldStatus := req.Entry.Json, req.Entry.Status
+	req.Entry.Json, err = mergeRelease(
+		req.Entry.Json, suggestion.Json, req.MergePolicy, req.ExtDB, req.ExtID)
+	if err != nil {
+		return
+	}
+	if err = m.evalEntryStatus(req, oldStatus); err != nil {
+		return
+	}
+	if err = m.store.UpdateEntry(txctx, req.Entry); err != nil {
+		return
+	}
+
+	if err = m.acceptSuggestionActors(txctx, req.Entry.ID, suggestion.Json); err != nil {
+		return
+	}
+	if req.Actors, err = m.store.EntryActors(txctx, req.Entry.ID); err != nil {
+		return
+	}
+
+	req.Accepted = &entity.AcceptedSuggestion{
+		EntryID:     req.Entry.ID,
+		ExtDB:       req.ExtDB,
+		ExtID:       req.ExtID,
+		MergePolicy: req.MergePolicy,
+		AcceptedAt:  time.Now().UTC()}
+	if err = m.store.SaveAcceptedSuggestion(txctx, req.Accepted); err != nil {
+		return
 	}
+
+	changed := []string{"actors", "accepted"}
+	if !equalJSON(oldJson, req.Entry.Json) {
+		changed = append([]string{"json"}, changed...)
+	}
+	if oldStatus != req.Entry.Status {
+		changed = append(changed, "status")
+	}
+	err = m.recordEvent(txctx, &entity.EntryEvent{
+		Type:      entity.EntryUpdated,
+		Cmd:       req.Cmd,
+		EntryID:   req.Entry.ID,
+		Path:      req.Entry.Path,
+		OldStatus: oldStatus,
+		NewStatus: req.Entry.Status,
+		Changed:   changed})
+	if err != nil {
+		return
+	}
+
+	return json.Marshal(req)
 }
 
-// Добавляет или заменяет графические объекты альбома.
-func syncEntryPictures(ctx context.Context, req *AudioDBRequest) error {
-	oldPictures, err := entity.EntryPictures(ctx, req.Entry.ID)
+// rejectSuggestion отвергает предложение `req.ExtDB`/`req.ExtID` в одной транзакции:
+// запись audio.suggestion переносится в audio.bad_suggestion, а акторы, известные только
+// по этому предложению, удаляются.
+// Возвращает актуальные списки предложений, отвергнутых предложений и акторов каталога.
+func (m *Dbm) rejectSuggestion(req *AudioDBRequest) (_ []byte, err error) {
+	txctx, tx, err := m.begin(m.ctx)
+	if err != nil {
+		return
+	}
+	defer m.completeTx(tx, &err)
+
+	if err = m.store.GetEntry(txctx, req.Entry); err != nil {
+		return
+	}
+	if err = checkNotFinalyzed(req.Entry); err != nil {
+		return
+	}
+	suggestion := &entity.Suggestion{EntryID: req.Entry.ID, ExtDB: req.ExtDB, ExtID: req.ExtID}
+	if err = m.store.GetSuggestion(txctx, suggestion); err != nil {
+		return
+	}
+	if err = m.store.DeleteSuggestion(txctx, suggestion); err != nil {
+		return
+	}
+	bad := &entity.BadSuggestion{EntryID: req.Entry.ID, ExtDB: req.ExtDB, ExtID: req.ExtID}
+	if err = m.store.CreateBadSuggestion(txctx, bad); err != nil {
+		return
+	}
+
+	if req.Suggestions, err = m.store.EntrySuggestions(txctx, req.Entry.ID); err != nil {
+		return
+	}
+	if err = m.dropSuggestionActors(txctx, req.Entry.ID, suggestion, req.Suggestions); err != nil {
+		return
+	}
+
+	if req.BadSuggestions, err = m.store.EntryBadSuggestions(txctx, req.Entry.ID); err != nil {
+		return
+	}
+	if req.Actors, err = m.store.EntryActors(txctx, req.Entry.ID); err != nil {
+		return
+	}
+
+	err = m.recordEvent(txctx, &entity.EntryEvent{
+		Type:      entity.EntryUpdated,
+		Cmd:       req.Cmd,
+		EntryID:   req.Entry.ID,
+		Path:      req.Entry.Path,
+		OldStatus: req.Entry.Status,
+		NewStatus: req.Entry.Status,
+		Changed:   []string{"suggestions", "bad_suggestions", "actors"}})
+	if err != nil {
+		return
+	}
+
+	return json.Marshal(req)
+}
+
+// listEntries возвращает страницу списка каталогов по условиям `req.Filter`.
+// Для получения следующей страницы клиент повторяет запрос, указав в фильтре курсор
+// из поля ответа `next_cursor`.
+func (m *Dbm) listEntries(req *AudioDBRequest) (_ []byte, err error) {
+	if req.Filter == nil {
+		req.Filter = &entity.EntryFilter{}
+	}
+	req.Entries, req.NextCursor, err = m.store.ListEntries(m.ctx, req.Filter)
+	if err != nil {
+		return
+	}
+	return json.Marshal(req)
+}
+
+// searchEntries выполняет поиск каталогов по метаданным релизов с условиями `req.Search`.
+func (m *Dbm) searchEntries(req *AudioDBRequest) (_ []byte, err error) {
+	if req.Search == nil {
+		return nil, entity.ErrEmptySearch
+	}
+	req.SearchResults, err = m.store.SearchEntries(m.ctx, req.Search)
+	if err != nil {
+		return
+	}
+	return json.Marshal(req)
+}
+
+// Завершает транзакцию откатом при наличии ошибки `*err` или фиксацией в противном случае.
+// Ошибка фиксации транзакции возвращается через `err`. После фиксации транзакции,
+// в которой были сохранены события, вызывается notifyEvents().
+func (m *Dbm) completeTx(tx entity.StoreTx, err *error) {
+	if *err != nil {
+		m.LogOnErrorWithContext(tx.Rollback(m.ctx), "Transaction rollback")
+		return
+	}
+	if *err = tx.Commit(m.ctx); *err == nil {
+		if cmd, ok := tx.(*cmdTx); ok && cmd.events {
+			m.notifyEvents()
+		}
+	}
+}
+
+// Помечает акторов предложений, упоминаемых в релизе `release`, как акторов каталога.
+func (m *Dbm) acceptSuggestionActors(ctx context.Context, entryID int, release []byte) error {
+	names, err := releaseActorNames(release)
 	if err != nil {
 		return err
 	}
+	actors, err := m.store.EntryActors(ctx, entryID)
+	if err != nil {
+		return err
+	}
+	for _, actor := range actors {
+		if actor.EntityMask&entity.SuggestionEntity == 0 ||
+			actor.EntityMask&entity.AlbumEntryEntity != 0 || !names[actor.Name] {
+			continue
+		}
+		actor.EntityMask |= entity.AlbumEntryEntity
+		if err = m.store.UpdateActor(ctx, actor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Удаляет акторов, которые упоминаются только в отвергнутом предложении `rejected`
+// и отсутствуют в релизе каталога и остальных предложениях `rest`.
+func (m *Dbm) dropSuggestionActors(
+	ctx context.Context, entryID int, rejected *entity.Suggestion, rest []*entity.Suggestion) error {
+
+	names, err := releaseActorNames(rejected.Json)
+	if err != nil {
+		return err
+	}
+	for _, suggestion := range rest {
+		other, err := releaseActorNames(suggestion.Json)
+		if err != nil {
+			return err
+		}
+		for name := range other {
+			delete(names, name)
+		}
+	}
+	actors, err := m.store.EntryActors(ctx, entryID)
+	if err != nil {
+		return err
+	}
+	for _, actor := range actors {
+		if actor.EntityMask != entity.SuggestionEntity || !names[actor.Name] {
+			continue
+		}
+		if err = m.store.DeleteActor(ctx, actor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Добавляет или заменяет графические объекты альбома.
+func (m *Dbm) syncEntryPictures(ctx context.Context, req *AudioDBRequest) (changed bool, err error) {
+	oldPictures, err := m.store.EntryPictures(ctx, req.Entry.ID)
+	if err != nil {
+		return false, err
+	}
 	for _, pict := range req.Pictures {
 		pict.EntID = req.Entry.ID
 	}
 	for _, pict := range oldPictures {
 		if !collection.Contains(pict, req.Pictures) {
-			if err = pict.Delete(ctx); err != nil {
-				return err
+			if err = m.store.DeletePicture(ctx, pict); err != nil {
+				return false, err
 			}
+			changed = true
 		}
 	}
 	for _, pict := range req.Pictures {
 		if !collection.Contains(pict, oldPictures) {
-			if err = pict.Create(ctx); err != nil {
-				return err
+			if err = m.store.CreatePicture(ctx, pict); err != nil {
+				return false, err
 			}
+			changed = true
 		}
 	}
-	return nil
+	return changed, nil
 }
 
 // Добавляет или заменяет идентификаторы акторов во внешних БД.
-func syncEntryActors(ctx context.Context, req *AudioDBRequest) error {
-	oldActors, err := entity.EntryActors(ctx, req.Entry.ID)
+func (m *Dbm) syncEntryActors(ctx context.Context, req *AudioDBRequest) (changed bool, err error) {
+	oldActors, err := m.store.EntryActors(ctx, req.Entry.ID)
 	if err != nil {
-		return err
+		return false, err
 	}
 	for _, actor := range req.Actors {
 		actor.EntryID = req.Entry.ID
 	}
 	for _, actor := range oldActors {
 		if !collection.Contains(actor, req.Actors) {
-			if err := actor.Delete(ctx); err != nil {
-				return err
+			if err := m.store.DeleteActor(ctx, actor); err != nil {
+				return false, err
 			}
+			changed = true
 		}
 	}
 	for _, actor := range req.Actors {
 		if !collection.Contains(actor, oldActors) {
-			if err := actor.Create(ctx); err != nil {
-				return err
+			if err := m.store.CreateActor(ctx, actor); err != nil {
+				return false, err
 			}
+			changed = true
 		}
 	}
-	return nil
+	return changed, nil
 }
 
 // Добавляет или удаляет online-предложения.
-func syncSuggestions(ctx context.Context, req *AudioDBRequest) error {
+func (m *Dbm) syncSuggestions(ctx context.Context, req *AudioDBRequest) (changed bool, err error) {
 	for _, suggestion := range req.Suggestions {
 		suggestion.EntryID = req.Entry.ID
 	}
-	oldSuggestions, err := entity.EntrySuggestions(ctx, req.Entry.ID)
+	oldSuggestions, err := m.store.EntrySuggestions(ctx, req.Entry.ID)
 	if err != nil {
-		return err
+		return false, err
 	}
 	for _, suggestion := range oldSuggestions {
 		if !collection.Contains(suggestion, req.Suggestions) {
-			if err := suggestion.Delete(ctx); err != nil {
-				return err
+			if err := m.store.DeleteSuggestion(ctx, suggestion); err != nil {
+				return false, err
 			}
+			changed = true
 		}
 	}
 	for _, suggestion := range req.Suggestions {
 		if !collection.Contains(suggestion, oldSuggestions) {
-			if err := suggestion.Create(ctx); err != nil {
-				return err
+			if err := m.store.CreateSuggestion(ctx, suggestion); err != nil {
+				return false, err
 			}
+			changed = true
 		}
 	}
-	return nil
+	return changed, nil
 }
 
 // Добавляет или удаляет исключения для online-предложений.
-func syncBadSuggestions(ctx context.Context, req *AudioDBRequest) error {
+func (m *Dbm) syncBadSuggestions(ctx context.Context, req *AudioDBRequest) (changed bool, err error) {
 	for _, badSuggestion := range req.BadSuggestions {
 		badSuggestion.EntryID = req.Entry.ID
 	}
-	oldBadSuggestions, err := entity.EntryBadSuggestions(ctx, req.Entry.ID)
+	oldBadSuggestions, err := m.store.EntryBadSuggestions(ctx, req.Entry.ID)
 	if err != nil {
-		return err
+		return false, err
 	}
 	for _, badSuggestion := range oldBadSuggestions {
 		if !collection.Contains(badSuggestion, req.BadSuggestions) {
-			if err := badSuggestion.Delete(ctx); err != nil {
-				return err
+			if err := m.store.DeleteBadSuggestion(ctx, badSuggestion); err != nil {
+				return false, err
 			}
+			changed = true
 		}
 	}
 	for _, badSuggestion := range req.BadSuggestions {
 		if !collection.Contains(badSuggestion, oldBadSuggestions) {
-			if err := badSuggestion.Create(ctx); err != nil {
-				return err
+			if err := m.store.CreateBadSuggestion(ctx, badSuggestion); err != nil {
+				return false, err
 			}
+			changed = true
 		}
 	}
-	return nil
+	return changed, nil
 }
@@ -0,0 +1,84 @@
+package dbm
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+)
+
+// Возвращает ошибку ErrAlreadyFinalyzed, если Entry финализирован.
+// Финализированный Entry может быть изменен только после команды `unfinalyze_entry`.
+func checkNotFinalyzed(entry *entity.AlbumEntry) error {
+	if entry.Status == entity.StatusFinalyzed {
+		return errors.Wrapf(ErrAlreadyFinalyzed, "entry_id=%d", entry.ID)
+	}
+	return nil
+}
+
+// Устанавливает версию `version` Entry из запроса в качестве ожидаемой при его изменении.
+// Нулевая версия означает, что проверка не выполняется: изменение применяется к состоянию
+// Entry, прочитанному в транзакции команды.
+func expectVersion(entry *entity.AlbumEntry, version int) {
+	if version != 0 {
+		entry.Version = version
+	}
+}
+
+// Проверяет изменение статуса Entry командами редактирования.
+// Статус "finalyzed" устанавливается только командой `finalyze_entry`.
+func checkEditStatus(from, to string) error {
+	if to == entity.StatusFinalyzed && from != entity.StatusFinalyzed {
+		return errors.Wrap(entity.ErrBadStatusTransition, "use finalyze_entry command")
+	}
+	return entity.CheckStatusTransition(from, to)
+}
+
+// unfinalyzeEntry открывает финализированный Entry для редактирования.
+// Новый статус Entry вычисляется по наличию обязательных тегов релиза.
+func (m *Dbm) unfinalyzeEntry(req *AudioDBRequest) (_ []byte, err error) {
+	txctx, tx, err := m.begin(m.ctx)
+	if err != nil {
+		return
+	}
+	defer m.completeTx(tx, &err)
+
+	expectedVersion := req.Entry.Version
+	if err = m.store.GetEntry(txctx, req.Entry); err != nil {
+		return
+	}
+	if req.Entry.Status != entity.StatusFinalyzed {
+		err = errors.Wrapf(entity.ErrBadStatusTransition, "entry_id=%d is not finalyzed", req.Entry.ID)
+		return
+	}
+	expectVersion(req.Entry, expectedVersion)
+	if err = m.store.SaveEntryRevision(txctx, req.Entry.ID, req.Cmd); err != nil {
+		return
+	}
+	oldStatus := req.Entry.Status
+	if err = m.evalEntryStatus(req, ""); err != nil {
+		return
+	}
+	if err = entity.CheckStatusTransition(oldStatus, req.Entry.Status); err != nil {
+		return
+	}
+	if err = m.store.UpdateEntry(txctx, req.Entry); err != nil {
+		return
+	}
+	req.Transitions = entity.StatusTransitions(req.Entry.Status)
+
+	err = m.recordEvent(txctx, &entity.EntryEvent{
+		Type:      entity.EntryUnfinalyzed,
+		Cmd:       req.Cmd,
+		EntryID:   req.Entry.ID,
+		Path:      req.Entry.Path,
+		OldStatus: oldStatus,
+		NewStatus: req.Entry.Status,
+		Changed:   []string{"status"}})
+	if err != nil {
+		return
+	}
+
+	return json.Marshal(req)
+}
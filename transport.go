@@ -0,0 +1,59 @@
+package dbm
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Transport доставляет запрос к сервису в формате JSON и возвращает ответ сервиса.
+// Ошибки выполнения команд возвращаются в ответе (см. AudioDBResponse.Err()),
+// ошибка RoundTrip означает, что ответ не получен.
+type Transport interface {
+	RoundTrip(ctx context.Context, body []byte) ([]byte, error)
+}
+
+// InProcTransport передает запросы сервису, работающему в том же процессе, без участия
+// брокера сообщений.
+// Запросы выполняются тем же диспетчером сервиса, что и запросы от брокера сообщений,
+// HTTP-шлюза и сервера gRPC: одновременно обрабатывается не более `workers` запросов
+// всех транспортов, а команды, относящиеся к одному и тому же Entry, выполняются
+// в порядке поступления независимо от транспорта.
+type InProcTransport struct {
+	m *Dbm
+}
+
+var _ Transport = (*InProcTransport)(nil)
+
+// NewInProcTransport создает транспорт для вызова команд сервиса `m` из кода Go.
+func NewInProcTransport(m *Dbm) *InProcTransport {
+	return &InProcTransport{m: m}
+}
+
+// RoundTrip выполняет запрос и возвращает ответ сервиса.
+// При отмене контекста до начала выполнения команда не выполняется; начатая команда
+// выполняется до конца, но вызов завершается с ошибкой контекста, не дожидаясь ответа.
+func (t *InProcTransport) RoundTrip(ctx context.Context, body []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var req AudioDBRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return t.m.errorAnswer(nil, err, "Message dispatcher"), nil
+	}
+
+	var data []byte
+	err := t.m.disp.DoContext(ctx, t.m.entryKey(&req), func() {
+		t.m.logRequest(&req)
+		data = t.m.handle(&req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Close ожидает завершения выполняемых запросов сервиса.
+func (t *InProcTransport) Close() {
+	t.m.disp.Wait()
+}
@@ -0,0 +1,113 @@
+package dbm
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+	srv "github.com/ytsiuryn/ds-microservice"
+)
+
+func TestInProcTransport(t *testing.T) {
+	m := New("", WithStore(entity.NewMemStore()), WithWorkers(4))
+	defer m.Close()
+	tr := NewInProcTransport(m)
+	defer tr.Close()
+	ctx := context.Background()
+
+	data, err := tr.RoundTrip(ctx, []byte(`{"cmd":"ping"}`))
+	require.NoError(t, err)
+	assert.Empty(t, data)
+
+	data, err = tr.RoundTrip(ctx, []byte(`{"cmd":"x","entry":{"id":1}}`))
+	require.NoError(t, err)
+	baseResp, err := srv.ParseErrorAnswer(data)
+	require.NoError(t, err)
+	assert.Equal(t, "Message dispatcher", baseResp.Context)
+
+	data, err = tr.RoundTrip(ctx, []byte(`{"cmd":`))
+	require.NoError(t, err)
+	resp, err := ParseAnswer(data)
+	require.NoError(t, err)
+	assert.ErrorIs(t, resp.Err(), ErrBadRequest)
+
+	resp = roundTrip(t, tr, NewAudioDBRequest("set_entry", &entity.AlbumEntry{Path: "a"}))
+	require.NoError(t, resp.Err())
+	id := resp.Entry.ID
+
+	// Команды одного Entry выполняются последовательно.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := NewAudioDBRequest("set_entry", &entity.AlbumEntry{ID: id, Path: "a"})
+			_, body, err := req.Create()
+			require.NoError(t, err)
+			data, err := tr.RoundTrip(ctx, body)
+			require.NoError(t, err)
+			resp, err := ParseAnswer(data)
+			require.NoError(t, err)
+			assert.NoError(t, resp.Err())
+		}()
+	}
+	wg.Wait()
+
+	req := NewAudioDBRequest("get_entry", &entity.AlbumEntry{ID: id})
+	resp = roundTrip(t, tr, req)
+	require.NoError(t, resp.Err())
+	assert.Equal(t, 9, resp.Entry.Version)
+
+	req = NewAudioDBRequest("set_entry", &entity.AlbumEntry{ID: id, Path: "b", Version: 1})
+	resp = roundTrip(t, tr, req)
+	assert.ErrorIs(t, resp.Err(), ErrConflict)
+	require.NotNil(t, resp.AudioDBRequest)
+	assert.Equal(t, "a", resp.Entry.Path)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = tr.RoundTrip(cancelled, []byte(`{"cmd":"ping"}`))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestInProcTransportSharedDispatcher(t *testing.T) {
+	m := New("", WithStore(entity.NewMemStore()), WithWorkers(1))
+	defer m.Close()
+	tr := NewInProcTransport(m)
+	defer tr.Close()
+
+	// единственный обработчик сервиса занят запросом другого транспорта
+	release := make(chan struct{})
+	m.disp.Dispatch("", func() { <-release })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req := NewAudioDBRequest("set_entry", &entity.AlbumEntry{
+		Path: "a", Status: entity.StatusWithoutMandatoryTags})
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+	_, err = tr.RoundTrip(ctx, body)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	close(release)
+	tr.Close()
+
+	// команда, срок ожидания которой истек до начала выполнения, не выполняется
+	resp := roundTrip(t, tr, NewAudioDBRequest("get_entry", &entity.AlbumEntry{Path: "a"}))
+	assert.ErrorIs(t, resp.Err(), ErrNotFound)
+}
+
+func roundTrip(t *testing.T, tr Transport, req *AudioDBRequest) *AudioDBResponse {
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+	data, err := tr.RoundTrip(context.Background(), body)
+	require.NoError(t, err)
+	resp, err := ParseAnswer(data)
+	require.NoError(t, err)
+	return resp
+}
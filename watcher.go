@@ -0,0 +1,351 @@
+package dbm
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	srv "github.com/ytsiuryn/ds-microservice"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+)
+
+// DefaultWatchDebounce задает интервал по умолчанию, в течение которого накапливаются
+// события файловой системы перед их обработкой.
+const DefaultWatchDebounce = 2 * time.Second
+
+// Наименование команды, от имени которой сохраняются ревизии и события изменений,
+// обнаруженных отслеживанием файловой системы.
+const watcherCmd = "fs_watcher"
+
+// WithWatcher включает отслеживание переименований, перемещений и удалений каталогов
+// библиотеки WithLibraryRoot. События файловой системы обрабатываются после паузы
+// в их поступлении длительностью `debounce` (DefaultWatchDebounce при нулевом значении).
+func WithWatcher(debounce time.Duration) Option {
+	return func(m *Dbm) {
+		if debounce <= 0 {
+			debounce = DefaultWatchDebounce
+		}
+		m.watchDebounce = debounce
+	}
+}
+
+// Перемещение каталога библиотеки.
+type dirMove struct {
+	from, to string
+}
+
+// Запускает отслеживание изменений каталогов библиотеки.
+// Перемещение каталога обрабатывается как команда `move_tree`, удаление - в соответствии
+// с действием WithOrphanAction для записей удаленного каталога и вложенных в него каталогов.
+func (m *Dbm) startWatcher() {
+	w, err := fsnotify.NewWatcher()
+	srv.FailOnError(err, "Failed to create a filesystem watcher")
+	snaps := dirSnapshots{}
+	srv.FailOnError(addWatchTree(w, m.libraryRoot, m.libraryRoot, snaps),
+		"Failed to watch library root")
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.stopWatcher = cancel
+	m.watcherDone = make(chan struct{})
+
+	go func() {
+		defer close(m.watcherDone)
+		defer w.Close()
+		var removed, created []string
+		changed := map[string]bool{}
+		timer := time.NewTimer(m.watchDebounce)
+		stopTimer(timer)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				rel, err := filepath.Rel(m.libraryRoot, ev.Name)
+				if err != nil {
+					continue
+				}
+				rel = filepath.ToSlash(rel)
+				// содержимое родительского каталога сравнивается при сопоставлении переименований
+				changed[path.Dir(rel)] = true
+				switch {
+				case ev.Op&fsnotify.Create != 0:
+					if info, err := os.Stat(ev.Name); err != nil || !info.IsDir() {
+						break
+					}
+					m.LogOnErrorWithContext(
+						addWatchTree(w, m.libraryRoot, ev.Name, snaps), "Filesystem watcher")
+					created = append(created, rel)
+				case ev.Op&(fsnotify.Rename|fsnotify.Remove) != 0:
+					// файлы не отслеживаются: их исчезновение учитывается содержимым каталога
+					if _, ok := snaps[rel]; !ok {
+						break
+					}
+					// наблюдение за перемещенным каталогом продолжилось бы под старым именем
+					w.Remove(ev.Name)
+					removed = append(removed, rel)
+				}
+				stopTimer(timer)
+				timer.Reset(m.watchDebounce)
+			case err, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+				m.LogOnErrorWithContext(err, "Filesystem watcher")
+			case <-timer.C:
+				for rel := range changed {
+					snaps.update(m.libraryRoot, rel)
+				}
+				moves, deleted := resolveDirChanges(removed, created, m.libraryDirExists, snaps.same)
+				for _, mv := range moves {
+					snaps.removeTree(mv.from)
+				}
+				for _, rel := range deleted {
+					snaps.removeTree(rel)
+				}
+				removed, created, changed = nil, nil, map[string]bool{}
+				if len(moves)+len(deleted) == 0 {
+					continue
+				}
+				// изменения путей не выполняются одновременно с командами сервиса
+				m.disp.Do(exclusiveKey, func() {
+					if ctx.Err() == nil {
+						m.applyDirChanges(ctx, moves, deleted)
+					}
+				})
+			}
+		}
+	}()
+}
+
+// Останавливает таймер и извлекает из его канала значение, если таймер успел сработать,
+// чтобы последующий Reset() не привел к ложному срабатыванию.
+func stopTimer(timer *time.Timer) {
+	if !timer.Stop() {
+		select {
+		case <-timer.C:
+		default:
+		}
+	}
+}
+
+// Проверяет существование каталога `rel` библиотеки.
+func (m *Dbm) libraryDirExists(rel string) bool {
+	_, err := os.Stat(filepath.Join(m.libraryRoot, filepath.FromSlash(rel)))
+	return err == nil
+}
+
+// Добавляет наблюдение за каталогом `dir` библиотеки `root` и всеми вложенными каталогами
+// и сохраняет их содержимое в `snaps`.
+func addWatchTree(w *fsnotify.Watcher, root, dir string, snaps dirSnapshots) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		snaps.add(root, path)
+		return w.Add(path)
+	})
+}
+
+// Применяет к БД изменения каталогов библиотеки.
+func (m *Dbm) applyDirChanges(ctx context.Context, moves []dirMove, deleted []string) {
+	for _, mv := range moves {
+		entries, _, err := m.applyMoveTree(ctx, watcherCmd, mv.from, mv.to, false)
+		if err != nil {
+			m.LogOnErrorWithContext(err, "Filesystem watcher")
+			continue
+		}
+		if len(entries) > 0 {
+			m.Log.Infof("%s: %q -> %q (%d entries)", watcherCmd, mv.from, mv.to, len(entries))
+		}
+	}
+	if len(deleted) > 0 {
+		counts, err := m.removeTrees(ctx, deleted)
+		if err != nil {
+			m.LogOnErrorWithContext(err, "Filesystem watcher")
+		}
+		for _, prefix := range deleted {
+			if n := counts[prefix]; n > 0 {
+				m.Log.Infof("%s: %q removed (%d entries, %s)", watcherCmd, prefix, n, m.orphanAction)
+			}
+		}
+	}
+}
+
+// Выполняет действие WithOrphanAction с записями деревьев удаленных каталогов `prefixes`
+// и возвращает число записей каждого дерева. Как и при сверке, действие не выполняется,
+// если в библиотеке не осталось каталогов альбомов или доля записей удаленных каталогов
+// превышает WithMaxOrphanRatio.
+func (m *Dbm) removeTrees(ctx context.Context, prefixes []string) (_ map[string]int, err error) {
+	if m.orphanAction == OrphanReport {
+		return nil, nil
+	}
+	folders, err := scanLibrary(m.libraryRoot)
+	if err != nil {
+		return
+	}
+
+	txctx, tx, err := m.begin(ctx)
+	if err != nil {
+		return
+	}
+	defer m.completeTx(tx, &err)
+
+	counts := map[string]int{}
+	var orphans []*entity.AlbumEntry
+	for _, prefix := range prefixes {
+		var entries []*entity.AlbumEntry
+		if entries, err = m.store.TreeEntries(txctx, prefix); err != nil {
+			return
+		}
+		counts[prefix] = len(entries)
+		orphans = append(orphans, entries...)
+	}
+	if len(orphans) == 0 {
+		return counts, nil
+	}
+	entries, err := m.store.LibraryEntries(txctx)
+	if err != nil {
+		return
+	}
+	if err = m.checkOrphans(m.orphanAction, len(folders), len(entries), len(orphans)); err != nil {
+		return
+	}
+	if err = m.applyOrphanAction(txctx, watcherCmd, m.orphanAction, orphans, time.Now().UTC()); err != nil {
+		return
+	}
+	return counts, nil
+}
+
+// resolveDirChanges сопоставляет исчезнувшие (`removed`) и появившиеся (`created`) каталоги
+// библиотеки. Каталог считается перемещенным, если ровно один появившийся каталог имеет
+// то же имя (перемещение) или, иначе, ровно один появившийся каталог имеет того же родителя
+// и совпадающее содержимое `same` (переименование). Остальные исчезнувшие каталоги
+// считаются удаленными. Вложенные каталоги перемещенных или удаленных каталогов
+// не учитываются.
+func resolveDirChanges(removed, created []string,
+	exists func(rel string) bool, same func(from, to string) bool) (moves []dirMove, deleted []string) {
+
+	var gone, appeared []string
+	for _, rel := range topDirs(removed) {
+		if !exists(rel) {
+			gone = append(gone, rel)
+		}
+	}
+	for _, rel := range topDirs(created) {
+		if exists(rel) {
+			appeared = append(appeared, rel)
+		}
+	}
+
+	for _, from := range gone {
+		i := uniqueMatch(appeared, func(to string) bool { return path.Base(to) == path.Base(from) })
+		if i < 0 {
+			i = uniqueMatch(appeared, func(to string) bool { return path.Dir(to) == path.Dir(from) })
+			if i >= 0 && !same(from, appeared[i]) {
+				i = -1
+			}
+		}
+		if i < 0 {
+			deleted = append(deleted, from)
+			continue
+		}
+		moves = append(moves, dirMove{from: from, to: appeared[i]})
+		appeared = append(appeared[:i], appeared[i+1:]...)
+	}
+	return
+}
+
+// Содержимое отслеживаемых каталогов библиотеки: пути каталогов относительно корня
+// библиотеки и перечни их файлов с размерами и вложенных каталогов.
+type dirSnapshots map[string]string
+
+// Сохраняет содержимое каталога `dir` библиотеки `root`.
+func (s dirSnapshots) add(root, dir string) {
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return
+	}
+	s.update(root, filepath.ToSlash(rel))
+}
+
+// Обновляет содержимое каталога `rel` библиотеки `root`. Содержимое недоступного каталога
+// сохраняется до его удаления из перечня, чтобы его можно было сравнить с содержимым
+// каталога после переименования.
+func (s dirSnapshots) update(root, rel string) {
+	entries, err := os.ReadDir(filepath.Join(root, filepath.FromSlash(rel)))
+	if err != nil {
+		return
+	}
+	var b strings.Builder
+	for _, ent := range entries {
+		b.WriteString(ent.Name())
+		if ent.IsDir() {
+			b.WriteString("/\n")
+			continue
+		}
+		if info, err := ent.Info(); err == nil {
+			fmt.Fprintf(&b, "\t%d", info.Size())
+		}
+		b.WriteByte('\n')
+	}
+	s[rel] = b.String()
+}
+
+// Удаляет из перечня каталог `rel` и вложенные в него каталоги.
+func (s dirSnapshots) removeTree(rel string) {
+	for p := range s {
+		if p == rel || strings.HasPrefix(p, rel+"/") {
+			delete(s, p)
+		}
+	}
+}
+
+// Проверяет совпадение содержимого каталогов `from` и `to`.
+func (s dirSnapshots) same(from, to string) bool {
+	a, ok := s[from]
+	b, ok2 := s[to]
+	return ok && ok2 && a == b
+}
+
+// Возвращает индекс единственного элемента `paths`, удовлетворяющего условию, или -1.
+func uniqueMatch(paths []string, match func(string) bool) int {
+	ret := -1
+	for i, p := range paths {
+		if match(p) {
+			if ret >= 0 {
+				return -1
+			}
+			ret = i
+		}
+	}
+	return ret
+}
+
+// Возвращает отсортированный список путей без повторов и путей вложенных каталогов.
+func topDirs(paths []string) []string {
+	sorted := append([]string(nil), paths...)
+	sort.Strings(sorted)
+	var ret []string
+	for _, p := range sorted {
+		if n := len(ret); n > 0 && (p == ret[n-1] || strings.HasPrefix(p, ret[n-1]+"/")) {
+			continue
+		}
+		ret = append(ret, p)
+	}
+	return ret
+}
@@ -0,0 +1,164 @@
+package dbm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ytsiuryn/ds-audiodbm/entity"
+)
+
+func TestResolveDirChanges(t *testing.T) {
+	existing := map[string]bool{
+		"Rock/Pink Floyd":        true,
+		"Rock/Pink Floyd/Meddle": true,
+		"Pink Floyd - Animals":   true,
+		"Queen/Jazz":             true,
+		"Queen/Innuendo":         true,
+		"Queen/News":             true,
+		"Queen/Live Killers":     true,
+	}
+	exists := func(rel string) bool { return existing[rel] }
+	contents := map[string]string{
+		"Animals":              "01.flac",
+		"Pink Floyd - Animals": "01.flac",
+		"Queen/Queen":          "01.flac",
+		"Queen/Live Killers":   "01.flac\t1",
+	}
+	same := func(from, to string) bool { return contents[from] == contents[to] }
+
+	moves, deleted := resolveDirChanges(
+		[]string{
+			"Pink Floyd/Meddle", "Pink Floyd", "Pink Floyd", // перемещение с вложенным каталогом
+			"Animals",               // переименование
+			"Queen/A Kind Of Magic", // удаление: неоднозначное переименование
+			"Queen/01.flac",         // удаление файла
+		},
+		[]string{"Rock/Pink Floyd", "Rock/Pink Floyd/Meddle", "Pink Floyd - Animals",
+			"Queen/Innuendo", "Queen/News", "Queen/Missing"},
+		exists, same)
+	assert.Equal(t, []dirMove{
+		{from: "Animals", to: "Pink Floyd - Animals"},
+		{from: "Pink Floyd", to: "Rock/Pink Floyd"},
+	}, moves)
+	assert.Equal(t, []string{"Queen/01.flac", "Queen/A Kind Of Magic"}, deleted)
+
+	// единственный появившийся каталог с другим содержимым не считается переименованным
+	moves, deleted = resolveDirChanges(
+		[]string{"Queen/Queen"}, []string{"Queen/Live Killers"}, exists, same)
+	assert.Empty(t, moves)
+	assert.Equal(t, []string{"Queen/Queen"}, deleted)
+}
+
+func TestDirSnapshots(t *testing.T) {
+	root := t.TempDir()
+	album := filepath.Join(root, "Queen", "Jazz")
+	require.NoError(t, os.MkdirAll(filepath.Join(album, "Scans"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(album, "01.flac"), []byte("1"), 0644))
+	snaps := dirSnapshots{}
+	snaps.add(root, album)
+	snaps.update(root, "Queen")
+
+	// переименованный каталог сохраняет содержимое
+	require.NoError(t, os.Rename(album, filepath.Join(root, "Queen", "1978 - Jazz")))
+	snaps.update(root, "Queen/Jazz")
+	snaps.update(root, "Queen/1978 - Jazz")
+	assert.True(t, snaps.same("Queen/Jazz", "Queen/1978 - Jazz"))
+	assert.False(t, snaps.same("Queen/Jazz", "Queen"))
+	assert.False(t, snaps.same("Queen/Jazz", "Queen/News"))
+
+	// изменение размера файла изменяет содержимое
+	require.NoError(t, ioutil.WriteFile(
+		filepath.Join(root, "Queen", "1978 - Jazz", "01.flac"), []byte("12"), 0644))
+	snaps.update(root, "Queen/1978 - Jazz")
+	assert.False(t, snaps.same("Queen/Jazz", "Queen/1978 - Jazz"))
+
+	snaps.removeTree("Queen")
+	assert.Empty(t, snaps)
+}
+
+func TestStopTimer(t *testing.T) {
+	timer := time.NewTimer(time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	stopTimer(timer)
+	timer.Reset(time.Hour)
+	select {
+	case <-timer.C:
+		t.Fatal("stale timer value")
+	case <-time.After(10 * time.Millisecond):
+	}
+	stopTimer(timer)
+}
+
+func TestWatcher(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"Queen/Jazz", "Queen/News"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0755))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(root, dir, "01.flac"), []byte(dir), 0644))
+	}
+	m := New("", WithStore(entity.NewMemStore()), WithLibraryRoot(root), WithWatcher(20*time.Millisecond))
+	defer m.Close()
+	ids := map[string]int{}
+	for _, path := range []string{"Queen/Jazz", "Queen/News"} {
+		ids[path] = executeCmd(t, m,
+			NewAudioDBRequest("set_entry", &entity.AlbumEntry{Path: path})).Entry.ID
+	}
+	m.startWatcher()
+	pathOf := func(id int) string {
+		return executeCmd(t, m, NewAudioDBRequest("get_entry", &entity.AlbumEntry{ID: id})).Entry.Path
+	}
+
+	// переименование каталога с тем же содержимым
+	require.NoError(t, os.Rename(
+		filepath.Join(root, "Queen", "Jazz"), filepath.Join(root, "Queen", "1978 - Jazz")))
+	assert.Eventually(t, func() bool { return pathOf(ids["Queen/Jazz"]) == "Queen/1978 - Jazz" },
+		2*time.Second, 10*time.Millisecond)
+
+	// удаление каталога и создание каталога с другим содержимым не считаются переименованием
+	require.NoError(t, os.RemoveAll(filepath.Join(root, "Queen", "News")))
+	other := filepath.Join(root, "Queen", "Innuendo")
+	require.NoError(t, os.Mkdir(other, 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(other, "01.flac"), []byte("Innuendo"), 0644))
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, "Queen/News", pathOf(ids["Queen/News"]))
+}
+
+func TestWatcherRefusesUnsafeOrphanAction(t *testing.T) {
+	root := t.TempDir()
+	dirs := []string{"Queen/Jazz", "Queen/News", "Queen/Innuendo", "Queen/Miracle"}
+	for _, dir := range dirs {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0755))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(root, dir, "01.flac"), []byte(dir), 0644))
+	}
+	m := New("", WithStore(entity.NewMemStore()), WithLibraryRoot(root),
+		WithOrphanAction(OrphanDelete), WithWatcher(20*time.Millisecond))
+	defer m.Close()
+	for _, path := range dirs {
+		executeCmd(t, m, NewAudioDBRequest("set_entry", &entity.AlbumEntry{Path: path}))
+	}
+	m.startWatcher()
+	countEntries := func() int {
+		return len(executeCmd(t, m, NewAudioDBRequest("list_entries", nil)).Entries)
+	}
+
+	// удаление файла не считается удалением каталога
+	require.NoError(t, os.Rename(filepath.Join(root, "Queen", "Jazz", "01.flac"),
+		filepath.Join(root, "Queen", "Jazz", "02.flac")))
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, 4, countEntries())
+
+	require.NoError(t, os.RemoveAll(filepath.Join(root, "Queen", "Miracle")))
+	assert.Eventually(t, func() bool { return countEntries() == 3 },
+		2*time.Second, 10*time.Millisecond)
+
+	// доля записей удаленных каталогов ограничена
+	require.NoError(t, os.RemoveAll(filepath.Join(root, "Queen", "Jazz")))
+	require.NoError(t, os.RemoveAll(filepath.Join(root, "Queen", "News")))
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, 3, countEntries())
+}